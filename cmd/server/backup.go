@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/aliaxy/byte-cabinet/internal/backup"
+	"github.com/aliaxy/byte-cabinet/internal/config"
+)
+
+// runBackup implements the backup command
+func runBackup(configPath string, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	prune := fs.Bool("prune", false, "apply the retention policy after the backup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, db, err := openDatabase(configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	manager := backup.NewManager(db, cfg.Backup, cfg.Upload.Path)
+	info, err := manager.Create(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backup written to %s/%s (%d bytes)\n", cfg.Backup.Dir, info.Name, info.Size)
+
+	if *prune {
+		removed, err := manager.Prune()
+		if err != nil {
+			return err
+		}
+		for _, name := range removed {
+			fmt.Printf("Removed %s\n", name)
+		}
+	}
+	return nil
+}
+
+// runRestore implements the restore command
+func runRestore(configPath string, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	force := fs.Bool("force", false, "confirm replacing the current database and uploads")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: restore --force <archive>")
+	}
+	if !*force {
+		return errors.New("restore replaces the current database and uploads; stop the server and pass --force to confirm")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Database.Driver != config.DriverSQLite {
+		return errors.New("restore only supports the sqlite database driver; use pg_restore for postgres")
+	}
+
+	manifest, err := backup.Restore(context.Background(), fs.Arg(0), cfg.Database.Path, cfg.Upload.Path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored backup from %s (schema version %d)\n",
+		manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"), manifest.SchemaVersion)
+	fmt.Println("The previous files were kept with a .pre-restore-<time> suffix")
+	return nil
+}
@@ -1,152 +1,193 @@
 package main
 
 import (
-	"log"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
 	"github.com/aliaxy/byte-cabinet/internal/config"
 	"github.com/aliaxy/byte-cabinet/internal/database"
-	"github.com/aliaxy/byte-cabinet/internal/handler"
-	"github.com/aliaxy/byte-cabinet/internal/middleware"
-	"github.com/aliaxy/byte-cabinet/internal/repository"
-	"github.com/aliaxy/byte-cabinet/internal/service"
 	"github.com/aliaxy/byte-cabinet/pkg/utils"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
+	"golang.org/x/term"
 )
 
+const usage = `Usage: byte-cabinet [--config path] <command> [arguments]
+
+Commands:
+  serve                                 Run the HTTP server (default)
+  migrate up                            Apply all pending migrations
+  migrate down --force                  Roll back all migrations (deletes all data)
+  migrate steps N                       Apply N migrations, or roll back -N
+  migrate version                       Show the applied and latest schema version
+  user create --username U --email E    Create a user (password read from stdin)
+  user reset-password --username U      Set a new password (read from stdin)
+  user list                             List all users
+  backup [--prune]                      Archive the database and uploads to backup.dir
+  restore --force <archive>             Restore a backup archive (server must be stopped)
+  reindex                               Rebuild indexes and refresh statistics
+  config check                          Validate the configuration
+
+Flags:
+`
+
 func main() {
-	// Load configuration
-	cfg, err := config.Load("")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	configPath := flag.String("config", "", "path to the config file (default: ./config.yaml or ./config/config.yaml)")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 || args[0] == "serve" {
+		serve(*configPath)
+		return
+	}
+
+	var err error
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(*configPath, args[1:])
+	case "user":
+		err = runUser(*configPath, args[1:])
+	case "backup":
+		err = runBackup(*configPath, args[1:])
+	case "restore":
+		err = runRestore(*configPath, args[1:])
+	case "reindex":
+		err = runReindex(*configPath)
+	case "config":
+		err = runConfig(*configPath, args[1:])
+	case "help":
+		flag.Usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+}
+
+// printError writes err to stderr, listing field errors on separate lines
+func printError(err error) {
+	appErr, ok := apperr.As(err)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %s\n", appErr.Message)
+	for _, fe := range appErr.Fields {
+		fmt.Fprintf(os.Stderr, "  - %s: %s\n", fe.Field, fe.Message)
+	}
+	if appErr.Kind == apperr.KindInternal && appErr.Err != nil {
+		fmt.Fprintf(os.Stderr, "  cause: %v\n", appErr.Err)
+	}
+}
+
+// openDatabase loads the configuration and opens the database without
+// running migrations
+func openDatabase(configPath string) (*config.Config, *database.DB, error) {
+	cfg, err := config.Load(configPath)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return nil, nil, err
 	}
 
-	// Initialize database
-	db, err := database.New(cfg.Database.Path)
+	db, err := database.Open(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	defer db.Close()
+	return cfg, db, nil
+}
 
-	// Run migrations
-	log.Println("📦 Running database migrations...")
-	if err := db.Migrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
-	}
-	log.Println("✅ Database migrations completed")
-
-	// Initialize JWT manager
-	jwtManager := utils.NewJWTManager(
-		cfg.JWT.Secret,
-		cfg.JWT.AccessTokenTTL,
-		cfg.JWT.RefreshTokenTTL,
-	)
-
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(db.DB)
-
-	// Initialize services
-	authService := service.NewAuthService(userRepo, jwtManager)
-
-	// Initialize handlers
-	authHandler := handler.NewAuthHandler(authService)
-
-	// Create Fiber app
-	app := fiber.New(fiber.Config{
-		AppName:      cfg.Blog.Title,
-		ErrorHandler: customErrorHandler,
-	})
-
-	// Global middleware
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
-	}))
-	app.Use(recover.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "*",
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
-		AllowCredentials: false,
-	}))
-
-	// Health check endpoint
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"success": true,
-			"data": fiber.Map{
-				"status":  "healthy",
-				"service": cfg.Blog.Title,
-			},
-		})
-	})
-
-	// API routes
-	api := app.Group("/api")
-	v1 := api.Group("/v1")
-
-	// Create auth middleware
-	authMiddleware := middleware.AuthMiddleware(jwtManager)
-
-	// Register routes
-	authHandler.RegisterRoutes(v1, authMiddleware)
-
-	// API welcome route
-	v1.Get("/", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"success": true,
-			"data": fiber.Map{
-				"message": "Welcome to " + cfg.Blog.Title + " API v1",
-				"version": "1.0.0",
-			},
-		})
-	})
-
-	// Graceful shutdown
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-		<-sigChan
-
-		log.Println("🛑 Shutting down server...")
-		if err := app.Shutdown(); err != nil {
-			log.Printf("Error during shutdown: %v", err)
+// newPasswordPolicy builds the password policy from the configuration
+func newPasswordPolicy(cfg *config.Config) *utils.PasswordPolicy {
+	return &utils.PasswordPolicy{
+		MinLength:        cfg.Password.MinLength,
+		MaxLength:        cfg.Password.MaxLength,
+		RequireUppercase: cfg.Password.RequireUppercase,
+		RequireLowercase: cfg.Password.RequireLowercase,
+		RequireDigit:     cfg.Password.RequireDigit,
+		RequireSymbol:    cfg.Password.RequireSymbol,
+		RejectCommon:     cfg.Password.RejectCommon,
+		RejectPersonal:   cfg.Password.RejectPersonal,
+	}
+}
+
+// readPassword reads a password from stdin, prompting on stderr so the
+// prompt does not end up in redirected output. Input is not echoed when
+// stdin is a terminal; otherwise the first line is read, so the password
+// can be piped in.
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	var password string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		b, err := term.ReadPassword(fd)
+		// The typed newline is not echoed either
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		password = string(b)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password: %w", err)
 		}
-	}()
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
 
-	// Start server
-	addr := cfg.Server.Address()
-	log.Printf("🚀 Server starting on %s", addr)
-	log.Printf("📝 Blog: %s", cfg.Blog.Title)
-	log.Printf("🔧 Mode: %s", cfg.Server.Mode)
+// runConfig implements the config subcommands
+func runConfig(configPath string, args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errors.New("usage: config check")
+	}
 
-	if err := app.Listen(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if _, err := config.Load(configPath); err != nil {
+		var verr *config.ValidationError
+		if errors.As(err, &verr) {
+			fmt.Fprintf(os.Stderr, "Configuration has %d problem(s):\n", len(verr.Problems))
+			for _, problem := range verr.Problems {
+				fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+			}
+			return errors.New("configuration is invalid")
+		}
+		return err
 	}
+
+	fmt.Println("Configuration is valid")
+	return nil
 }
 
-// customErrorHandler handles errors globally
-func customErrorHandler(c *fiber.Ctx, err error) error {
-	// Default status code
-	code := fiber.StatusInternalServerError
-	message := "Internal Server Error"
-
-	// Check if it's a Fiber error
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-		message = e.Message
-	}
-
-	return c.Status(code).JSON(fiber.Map{
-		"success": false,
-		"error": fiber.Map{
-			"code":    code,
-			"message": message,
-		},
-	})
+// runReindex rebuilds the database indexes
+func runReindex(configPath string) error {
+	_, db, err := openDatabase(configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Reindex(context.Background()); err != nil {
+		return err
+	}
+
+	fmt.Println("Indexes rebuilt and statistics refreshed")
+	return nil
 }
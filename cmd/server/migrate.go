@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+)
+
+// runMigrate implements the migrate subcommands
+func runMigrate(configPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down|steps N|version")
+	}
+
+	_, db, err := openDatabase(configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		if err := db.Migrate(); err != nil {
+			return err
+		}
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		force := fs.Bool("force", false, "confirm rolling back every migration")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if !*force {
+			return errors.New("migrate down drops all tables and data; pass --force to confirm")
+		}
+		if err := db.MigrateDown(); err != nil {
+			return err
+		}
+
+	case "steps":
+		if len(args) != 2 {
+			return errors.New("usage: migrate steps N")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n == 0 {
+			return fmt.Errorf("invalid step count %q", args[1])
+		}
+		if err := db.MigrateSteps(n); err != nil {
+			return err
+		}
+
+	case "version":
+		// Reported below
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	return printMigrationStatus(db)
+}
+
+// printMigrationStatus prints the applied and latest schema versions
+func printMigrationStatus(db *database.DB) error {
+	status, err := db.MigrationStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Schema version: %d (latest %d)\n", status.Current, status.Latest)
+	if status.Dirty {
+		fmt.Println("Warning: the last migration failed and left the database dirty")
+	} else if status.Pending() {
+		fmt.Printf("%d migration(s) pending\n", status.Latest-status.Current)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/backup"
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/handler"
+	"github.com/aliaxy/byte-cabinet/internal/health"
+	"github.com/aliaxy/byte-cabinet/internal/lifecycle"
+	"github.com/aliaxy/byte-cabinet/internal/maintenance"
+	"github.com/aliaxy/byte-cabinet/internal/metrics"
+	"github.com/aliaxy/byte-cabinet/internal/middleware"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+	"github.com/aliaxy/byte-cabinet/internal/service"
+	"github.com/aliaxy/byte-cabinet/internal/tlsutil"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+	"github.com/aliaxy/byte-cabinet/internal/views"
+	"github.com/aliaxy/byte-cabinet/pkg/logger"
+	"github.com/aliaxy/byte-cabinet/pkg/utils"
+	"github.com/aliaxy/byte-cabinet/web"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/valyala/fasthttp"
+)
+
+// serve runs the HTTP server until it receives SIGINT or SIGTERM
+func serve(configPath string) {
+	// Load configuration
+	cfgManager, err := config.NewManager(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := cfgManager.Config()
+
+	// Initialize logger
+	log, err := logger.New(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(log)
+
+	// Initialize tracing before the database so SQL spans use the provider
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("failed to initialize tracing", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("failed to flush traces", slog.Any("error", err))
+		}
+	}()
+
+	// Initialize database
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		fatal("failed to connect to database", err)
+	}
+	defer db.Close()
+
+	// Run migrations
+	log.Info("running database migrations")
+	if err := db.Migrate(); err != nil {
+		fatal("failed to run migrations", err)
+	}
+	log.Info("database migrations completed")
+
+	// Create the upload directory up front; the readiness check only
+	// verifies it stays writable
+	if err := os.MkdirAll(cfg.Upload.Path, 0755); err != nil {
+		fatal("failed to create upload directory", err)
+	}
+
+	// Background workers are stopped after the server and before the database
+	workers := lifecycle.New()
+
+	// Initialize JWT manager
+	jwtManager := utils.NewJWTManager(
+		cfg.JWT.Secret,
+		cfg.JWT.AccessTokenTTL,
+		cfg.JWT.RefreshTokenTTL,
+	)
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+
+	// Initialize services
+	authService := service.NewAuthService(userRepo, jwtManager, newPasswordPolicy(cfg), cfg.Password.BcryptCost)
+	statsService := service.NewStatsService(statsRepo)
+
+	// Initialize handlers
+	authHandler := handler.NewAuthHandler(authService, cfg.JWT.Cookie)
+	backupManager := backup.NewManager(db, cfg.Backup, cfg.Upload.Path)
+	backupHandler := handler.NewBackupHandler(backupManager)
+	maintenanceManager := maintenance.NewManager(db, cfg.Maintenance)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceManager)
+	viewTracker := views.NewTracker(db, cfg.Views, cfg.Blog.URL)
+	viewHandler := handler.NewViewHandler(viewTracker)
+	statsHandler := handler.NewStatsHandler(statsService)
+	healthHandler := handler.NewHealthHandler(health.NewChecker(
+		cfg.Health.CheckTimeout,
+		health.DatabaseCheck(db),
+		health.MigrationCheck(db),
+		health.WritableDirCheck("upload_dir", cfg.Upload.Path),
+		health.DiskSpaceCheck("disk_space", dataDir(cfg), cfg.Health.MinFreeDisk),
+	), func() string { return cfgManager.Config().Blog.Title })
+
+	// The Forwarded header is translated to X-Forwarded-For by middleware
+	proxyHeader := cfg.Server.ProxyHeader
+	if proxyHeader == config.ProxyHeaderForwarded {
+		proxyHeader = fiber.HeaderXForwardedFor
+	}
+
+	// Create Fiber app
+	app := fiber.New(fiber.Config{
+		AppName:      cfg.Blog.Title,
+		ErrorHandler: middleware.ErrorHandler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		BodyLimit:    cfg.BodyLimit(),
+
+		// Proxy headers are only honoured from trusted proxies
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		ProxyHeader:             proxyHeader,
+		EnableIPValidation:      true,
+	})
+
+	// Backup archives can take far longer to send than server.write_timeout
+	setWriteTimeout(app, "/api/v1/admin/backups/", cfg.Backup.DownloadTimeout)
+
+	// Initialize metrics
+	appMetrics := metrics.New(db.Writer().DB)
+	if reader := db.Reader(); reader != db.Writer() {
+		appMetrics.Register(collectors.NewDBStatsCollector(reader.DB, "reader"))
+	}
+	appMetrics.Register(metrics.NewBusinessCollector(statsRepo, cfg.Upload.Path))
+
+	// Global middleware
+	app.Use(middleware.ProxyHeaders(&cfg.Server))
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Tracing())
+	if cfg.Metrics.Enabled {
+		app.Use(appMetrics.Middleware())
+	}
+	app.Use(middleware.RequestLogger(log))
+	app.Use(recover.New())
+	app.Use(middleware.SecurityHeaders(cfg.Security, cfg.Server.IsProduction()))
+	corsHandler, err := middleware.CORS(&cfg.Server)
+	if err != nil {
+		fatal("failed to configure CORS", err)
+	}
+	cors := middleware.NewReloadable(corsHandler)
+	app.Use(cors.Handler())
+
+	// Health check endpoints
+	healthHandler.RegisterRoutes(app)
+
+	// Metrics endpoint
+	if cfg.Metrics.Enabled {
+		app.Get(cfg.Metrics.Path, appMetrics.Handler(cfg.Metrics.Token))
+	}
+
+	// API routes
+	api := app.Group("/api")
+	v1 := api.Group("/v1")
+
+	// Double-submit CSRF check for requests authenticated by cookies
+	v1.Use(middleware.CSRFProtection())
+
+	// Create auth middleware
+	authMiddleware := middleware.AuthMiddleware(jwtManager)
+
+	// Register routes
+	authHandler.RegisterRoutes(v1, authMiddleware)
+	backupHandler.RegisterRoutes(v1, authMiddleware)
+	maintenanceHandler.RegisterRoutes(v1, authMiddleware)
+	statsHandler.RegisterRoutes(v1, authMiddleware)
+	if cfg.Views.Enabled {
+		viewHandler.RegisterRoutes(v1)
+	}
+
+	// API welcome route
+	v1.Get("/", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": fiber.Map{
+				"message": "Welcome to " + cfgManager.Config().Blog.Title + " API v1",
+				"version": "1.0.0",
+			},
+		})
+	})
+
+	// Scheduled backups
+	if cfg.Backup.Enabled {
+		workers.Go("backup-scheduler", backupManager.Run)
+	}
+
+	// Scheduled database maintenance
+	if cfg.Maintenance.Enabled {
+		workers.Go("maintenance-scheduler", maintenanceManager.Run)
+	}
+
+	// Buffered view counts, flushed once more when workers are stopped
+	if cfg.Views.Enabled {
+		workers.Go("view-flusher", viewTracker.Run)
+	}
+
+	// Frontend embedded with the embedweb build tag, registered last so it
+	// only handles requests no route matched
+	if dist := web.Dist(); dist != nil {
+		app.Use(middleware.SPA(dist, "/api"))
+		log.Info("serving embedded frontend")
+	}
+
+	// Apply hot-reloadable settings when the config file changes
+	cfgManager.Subscribe(func(newCfg *config.Config) {
+		if err := logger.SetLevel(newCfg.Log.Level); err != nil {
+			log.Error("failed to apply log level", slog.Any("error", err))
+		}
+		corsHandler, err := middleware.CORS(&newCfg.Server)
+		if err != nil {
+			log.Error("failed to apply CORS settings, keeping the previous ones", slog.Any("error", err))
+			return
+		}
+		cors.Swap(corsHandler)
+	})
+	cfgManager.Watch()
+
+	// Graceful shutdown: stop accepting connections and wait for in-flight
+	// requests, then background workers. The shutdown timeout bounds both
+	// together, so the deadline is set once here.
+	var shutdownDeadline time.Time
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		<-sigChan
+
+		log.Info("shutting down server", slog.Duration("timeout", cfg.Server.ShutdownTimeout))
+		shutdownDeadline = time.Now().Add(cfg.Server.ShutdownTimeout)
+		ctx, cancel := context.WithDeadline(context.Background(), shutdownDeadline)
+		defer cancel()
+		if err := app.ShutdownWithContext(ctx); err != nil {
+			log.Error("error during shutdown", slog.Any("error", err))
+		}
+	}()
+
+	// Start server
+	log.Info("server starting",
+		slog.String("addr", cfg.Server.Address()),
+		slog.Bool("tls", cfg.Server.TLS.Enabled),
+		slog.String("blog", cfg.Blog.Title),
+		slog.String("mode", cfg.Server.Mode),
+	)
+
+	if err := listen(app, &cfg.Server, workers); err != nil {
+		fatal("failed to start server", err)
+	}
+	<-shutdownDone
+
+	// Drain background workers before the deferred database close, in
+	// whatever time the HTTP shutdown left
+	ctx, cancel := context.WithDeadline(context.Background(), shutdownDeadline)
+	defer cancel()
+	if err := workers.Shutdown(ctx); err != nil {
+		log.Error("error stopping background workers", slog.Any("error", err))
+	}
+	log.Info("server stopped")
+}
+
+// listen serves the app over HTTP, or over HTTPS with certificate reloading
+// and an optional HTTP to HTTPS redirect listener when TLS is enabled.
+// It blocks until the server is shut down.
+func listen(app *fiber.App, cfg *config.ServerConfig, workers *lifecycle.Manager) error {
+	if !cfg.TLS.Enabled {
+		return app.Listen(cfg.Address())
+	}
+
+	reloader, err := tlsutil.NewCertReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := tlsutil.NewConfig(reloader, cfg.TLS.MinVersion)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", cfg.Address())
+	if err != nil {
+		return err
+	}
+
+	workers.Go("tls-cert-reloader", func(ctx context.Context) {
+		reloader.Watch(ctx, cfg.TLS.ReloadInterval)
+	})
+
+	if cfg.TLS.RedirectHTTP {
+		redirect := tlsutil.NewRedirectServer(cfg.HTTPAddress(), cfg.Port)
+		workers.Go("http-redirect", func(ctx context.Context) {
+			go func() {
+				if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					slog.Error("HTTP redirect listener failed", slog.Any("error", err))
+				}
+			}()
+			<-ctx.Done()
+			_ = redirect.Close()
+		})
+		slog.Info("redirecting HTTP to HTTPS", slog.String("addr", redirect.Addr))
+	}
+
+	return app.Listener(tls.NewListener(ln, tlsConfig))
+}
+
+// fatal logs the error and exits the process
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
+
+// dataDir returns the local directory whose free space matters most: the
+// SQLite database directory, or the upload directory when the database is
+// remote
+func dataDir(cfg *config.Config) string {
+	if cfg.Database.Driver == config.DriverSQLite {
+		return filepath.Dir(cfg.Database.Path)
+	}
+	return cfg.Upload.Path
+}
+
+// setWriteTimeout overrides server.write_timeout for GET requests below
+// prefix. fasthttp sets a single write deadline for the whole response, and
+// the override must be chosen from the request headers, before routing.
+func setWriteTimeout(app *fiber.App, prefix string, timeout time.Duration) {
+	app.Server().HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		if header.IsGet() && bytes.HasPrefix(header.RequestURI(), []byte(prefix)) {
+			return fasthttp.RequestConfig{WriteTimeout: timeout}
+		}
+		return fasthttp.RequestConfig{}
+	}
+}
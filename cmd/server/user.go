@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+	"github.com/aliaxy/byte-cabinet/internal/service"
+	"github.com/aliaxy/byte-cabinet/internal/validation"
+)
+
+// runUser implements the user subcommands
+func runUser(configPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: user create|reset-password|list")
+	}
+
+	cfg, db, err := openDatabase(configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	userService := service.NewUserService(
+		db,
+		repository.NewUserRepository(db),
+		newPasswordPolicy(cfg),
+		cfg.Password.BcryptCost,
+	)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		return createUser(ctx, userService, args[1:])
+	case "reset-password":
+		return resetPassword(ctx, userService, args[1:])
+	case "list":
+		return listUsers(ctx, userService)
+	default:
+		return fmt.Errorf("unknown user command %q", args[0])
+	}
+}
+
+// createUser handles "user create"
+func createUser(ctx context.Context, userService *service.UserService, args []string) error {
+	var req model.UserCreate
+
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	fs.StringVar(&req.Username, "username", "", "login name")
+	fs.StringVar(&req.Email, "email", "", "email address")
+	fs.StringVar(&req.DisplayName, "display-name", "", "display name (default: username)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	password, err := readPassword("Password: ")
+	if err != nil {
+		return err
+	}
+	req.Password = password
+
+	if err := validation.Validator().Struct(&req); err != nil {
+		return apperr.Validation("Invalid user data", err)
+	}
+
+	user, err := userService.Create(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created user %q (id %d)\n", user.Username, user.ID)
+	return nil
+}
+
+// resetPassword handles "user reset-password"
+func resetPassword(ctx context.Context, userService *service.UserService, args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	username := fs.String("username", "", "login name of the user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("--username is required")
+	}
+
+	password, err := readPassword("New password: ")
+	if err != nil {
+		return err
+	}
+
+	if err := userService.ResetPassword(ctx, *username, password); err != nil {
+		return err
+	}
+
+	fmt.Printf("Password of %q has been reset\n", *username)
+	return nil
+}
+
+// listUsers handles "user list"
+func listUsers(ctx context.Context, userService *service.UserService) error {
+	users, err := userService.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tDISPLAY NAME\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			u.ID, u.Username, u.Email, u.DisplayName, u.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,183 @@
+package apperr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Kind classifies an error; the HTTP layer maps each kind to a status code
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindRateLimited
+	KindUnavailable
+)
+
+// String returns the kind name
+func (k Kind) String() string {
+	switch k {
+	case KindBadRequest:
+		return "bad_request"
+	case KindValidation:
+		return "validation"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindUnavailable:
+		return "unavailable"
+	default:
+		return "internal"
+	}
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string
+	Rule    string // validation rule that failed, e.g. "min"
+	Param   string // rule parameter, e.g. "8"
+	Message string
+}
+
+// Error is a typed application error.
+// Message is safe to show to users; the wrapped cause is only logged.
+type Error struct {
+	Kind    Kind
+	Code    string // optional machine-readable code overriding the kind's default
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// New creates an error of the given kind with a user-facing message
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap creates an error of the given kind that wraps cause
+func Wrap(cause error, kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message, Err: cause}
+}
+
+// Internal wraps an unexpected error; its details are never shown to users
+func Internal(cause error) *Error {
+	return &Error{Kind: KindInternal, Message: "An internal error occurred", Err: cause}
+}
+
+// Validation creates a validation error. When cause is a
+// validator.ValidationErrors, a FieldError is added for each failed field.
+func Validation(message string, cause error) *Error {
+	e := &Error{Kind: KindValidation, Message: message, Err: cause}
+
+	var verrs validator.ValidationErrors
+	if errors.As(cause, &verrs) {
+		for _, fe := range verrs {
+			e.Fields = append(e.Fields, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: defaultFieldMessage(fe),
+			})
+		}
+	}
+
+	return e
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the wrapped cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches another *Error with the same kind, code and message, so
+// sentinel errors still match after WithCause or WithFields
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code && e.Message == t.Message
+}
+
+// WithCause returns a copy of the error wrapping cause
+func (e *Error) WithCause(cause error) *Error {
+	c := *e
+	c.Err = cause
+	return &c
+}
+
+// WithCode returns a copy of the error with a specific code
+func (e *Error) WithCode(code string) *Error {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// WithFields returns a copy of the error with additional field errors
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &c
+}
+
+// As returns the first *Error in err's chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first *Error in err's chain,
+// or KindInternal for any other error
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// defaultFieldMessage renders an English message for a failed validation rule
+func defaultFieldMessage(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "url":
+		return field + " must be a valid URL"
+	case "min":
+		return field + " must be at least " + fe.Param() + " characters"
+	case "max":
+		return field + " must be at most " + fe.Param() + " characters"
+	case "oneof":
+		return field + " must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return field + " failed the " + fe.Tag() + " rule"
+	}
+}
@@ -0,0 +1,264 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/buildinfo"
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+)
+
+// Archive layout
+const (
+	manifestName = "manifest.json"
+	databaseName = "byte-cabinet.db"
+	uploadsDir   = "uploads"
+)
+
+// File naming: byte-cabinet-20060102T150405Z.tar.gz
+const (
+	filePrefix = "byte-cabinet-"
+	fileSuffix = ".tar.gz"
+	timeLayout = "20060102T150405Z"
+)
+
+// ErrNotFound is returned for unknown backup names
+var ErrNotFound = errors.New("backup not found")
+
+// Manifest describes the contents of a backup archive
+type Manifest struct {
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion uint      `json:"schema_version"`
+	AppVersion    string    `json:"app_version"`
+	Uploads       bool      `json:"uploads"`
+}
+
+// Info describes a backup archive on disk
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Manager creates, lists and prunes backups
+type Manager struct {
+	db         *database.DB
+	cfg        config.BackupConfig
+	uploadPath string
+
+	mu sync.Mutex // serialises backups and pruning
+}
+
+// NewManager creates a backup manager writing to cfg.Dir
+func NewManager(db *database.DB, cfg config.BackupConfig, uploadPath string) *Manager {
+	return &Manager{
+		db:         db,
+		cfg:        cfg,
+		uploadPath: uploadPath,
+	}
+}
+
+// Create writes a new backup archive containing a consistent snapshot of
+// the database and, if configured, the upload directory. It is safe to run
+// while the server is handling requests.
+func (m *Manager) Create(ctx context.Context) (*Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	name := filePrefix + now.Format(timeLayout) + fileSuffix
+	path := filepath.Join(m.cfg.Dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", name)
+	}
+
+	status, err := m.db.MigrationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot the database next to the archive, then bundle it
+	snapshot := filepath.Join(m.cfg.Dir, ".snapshot-"+now.Format(timeLayout)+".db")
+	defer os.Remove(snapshot)
+	if err := m.db.BackupTo(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{
+		CreatedAt:     now,
+		SchemaVersion: status.Current,
+		AppVersion:    buildinfo.Get().Version,
+		Uploads:       m.cfg.IncludeUploads,
+	}
+
+	// Write to a temporary file so a failed backup never looks complete
+	tmp, err := os.CreateTemp(m.cfg.Dir, ".tmp-*"+fileSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := m.writeArchive(tmp, manifest, snapshot); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store backup: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Name: name, Size: info.Size(), CreatedAt: now}, nil
+}
+
+// writeArchive writes the manifest, database snapshot and uploads to w
+func (m *Manager) writeArchive(w io.Writer, manifest Manifest, snapshot string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeBytes(tw, manifestName, data, manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := writeFile(tw, databaseName, snapshot); err != nil {
+		return err
+	}
+
+	if manifest.Uploads {
+		if err := writeDir(tw, uploadsDir, m.uploadPath); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// List returns the backups in the backup directory, newest first
+func (m *Manager) List() ([]Info, error) {
+	entries, err := os.ReadDir(m.cfg.Dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Info{}, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	backups := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		createdAt, ok := parseName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Info{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Path returns the file path of the named backup. Only names produced by
+// Create are accepted, so the result always lies inside the backup directory.
+func (m *Manager) Path(name string) (string, error) {
+	if _, ok := parseName(name); !ok {
+		return "", ErrNotFound
+	}
+	path := filepath.Join(m.cfg.Dir, name)
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Run creates a backup every configured interval and prunes old ones until
+// ctx is done
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.runScheduled(ctx)
+		}
+	}
+}
+
+// runScheduled takes one scheduled backup and applies retention
+func (m *Manager) runScheduled(ctx context.Context) {
+	info, err := m.Create(ctx)
+	if err != nil {
+		slog.Error("scheduled backup failed", slog.Any("error", err))
+		return
+	}
+	slog.Info("backup created", slog.String("name", info.Name), slog.Int64("size", info.Size))
+
+	removed, err := m.Prune()
+	if err != nil {
+		slog.Error("failed to prune backups", slog.Any("error", err))
+		return
+	}
+	for _, name := range removed {
+		slog.Info("backup removed by retention", slog.String("name", name))
+	}
+}
+
+// parseName extracts the creation time from a backup file name
+func parseName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+	t, err := time.Parse(timeLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
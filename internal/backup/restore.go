@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+)
+
+// Restore replaces the database at dbPath, and the upload directory when
+// the backup contains it, with the contents of the archive. The archive is
+// fully extracted and checked (manifest, SQLite integrity, schema version
+// supported by this binary) before anything is replaced. The replaced files
+// are kept next to the originals with a .pre-restore-<time> suffix.
+//
+// Each part is staged next to its destination so it can be moved into
+// place with a rename, even when the uploads live on another file system.
+// If any step of the swap fails, the completed ones are undone and the
+// original files are put back.
+//
+// The server must not be running while restoring.
+func Restore(ctx context.Context, archive, dbPath, uploadPath string) (*Manifest, error) {
+	staging, err := os.MkdirTemp(filepath.Dir(dbPath), ".restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	uploadParent := filepath.Dir(filepath.Clean(uploadPath))
+	if err := os.MkdirAll(uploadParent, 0755); err != nil {
+		return nil, err
+	}
+	uploadStaging, err := os.MkdirTemp(uploadParent, ".restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload staging directory: %w", err)
+	}
+	defer os.RemoveAll(uploadStaging)
+
+	if err := extractArchive(archive, staging, uploadStaging); err != nil {
+		return nil, err
+	}
+
+	stagedUploads := filepath.Join(uploadStaging, uploadsDir)
+	manifest, err := readManifest(staging, stagedUploads)
+	if err != nil {
+		return nil, err
+	}
+
+	stagedDB := filepath.Join(staging, databaseName)
+	if err := checkDatabase(ctx, stagedDB, manifest); err != nil {
+		return nil, err
+	}
+
+	j := &journal{suffix: ".pre-restore-" + time.Now().UTC().Format(timeLayout)}
+	if err := j.restore(dbPath, stagedDB, uploadPath, stagedUploads, manifest.Uploads); err != nil {
+		if rbErr := j.rollback(); rbErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("rollback failed, restore the %s files manually: %w", j.suffix, rbErr))
+		}
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// extractArchive unpacks the backup archive into dir, except for the
+// uploads, which are unpacked into uploadDir
+func extractArchive(archive, dir, uploadDir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	return extract(tar.NewReader(gz), func(name string) string {
+		if name == uploadsDir || strings.HasPrefix(name, uploadsDir+"/") {
+			return uploadDir
+		}
+		return dir
+	})
+}
+
+// readManifest reads and checks the manifest of an extracted backup
+func readManifest(dir, uploads string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("backup archive has no manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+
+	if manifest.Uploads {
+		if _, err := os.Stat(uploads); err != nil {
+			return nil, fmt.Errorf("backup archive is missing the uploads directory")
+		}
+	}
+	return &manifest, nil
+}
+
+// checkDatabase verifies the integrity and schema version of the staged
+// database
+func checkDatabase(ctx context.Context, path string, manifest *Manifest) error {
+	db, err := database.New(path)
+	if err != nil {
+		return fmt.Errorf("backup database cannot be opened: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.GetContext(ctx, &result, `PRAGMA integrity_check`); err != nil {
+		return fmt.Errorf("backup database integrity check failed: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup database is corrupt: %s", result)
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("backup database has a failed migration (version %d)", status.Current)
+	}
+	if status.Current != manifest.SchemaVersion {
+		return fmt.Errorf("backup schema version %d does not match its manifest (%d)",
+			status.Current, manifest.SchemaVersion)
+	}
+	if status.Current > status.Latest {
+		return fmt.Errorf("backup schema version %d is newer than this binary supports (%d)",
+			status.Current, status.Latest)
+	}
+	return nil
+}
+
+// journal records the renames of a restore so they can be undone
+type journal struct {
+	suffix  string
+	entries []journalEntry
+}
+
+// journalEntry is one completed rename: either path was moved aside to
+// path+suffix, or staged content was moved into path
+type journalEntry struct {
+	path   string
+	placed bool
+}
+
+// restore moves the staged database, and the uploads if included, into
+// place. Stale WAL files are moved aside too, since they would otherwise be
+// replayed into the restored database.
+func (j *journal) restore(dbPath, stagedDB, uploadPath, stagedUploads string, uploads bool) error {
+	for _, path := range []string{dbPath + "-wal", dbPath + "-shm", dbPath} {
+		if err := j.moveAside(path); err != nil {
+			return err
+		}
+	}
+	if err := j.place(stagedDB, dbPath); err != nil {
+		return err
+	}
+
+	if uploads {
+		if err := j.moveAside(uploadPath); err != nil {
+			return err
+		}
+		if err := j.place(stagedUploads, uploadPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveAside renames path to path+suffix if it exists
+func (j *journal) moveAside(path string) error {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := os.Rename(path, path+j.suffix); err != nil {
+		return fmt.Errorf("failed to move aside %s: %w", path, err)
+	}
+	j.entries = append(j.entries, journalEntry{path: path})
+	return nil
+}
+
+// place moves the staged src to dst
+func (j *journal) place(src, dst string) error {
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", dst, err)
+	}
+	j.entries = append(j.entries, journalEntry{path: dst, placed: true})
+	return nil
+}
+
+// rollback undoes the recorded renames in reverse order, removing restored
+// content and putting the moved-aside originals back
+func (j *journal) rollback() error {
+	var errs []error
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		e := j.entries[i]
+		var err error
+		if e.placed {
+			err = os.RemoveAll(e.path)
+		} else {
+			err = os.Rename(e.path+j.suffix, e.path)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Prune deletes backups not kept by the retention policy and returns the
+// names of the deleted backups. The newest backup of each of the last
+// KeepHourly hours, KeepDaily days and KeepWeekly ISO weeks is kept, as is
+// the newest backup overall. With all three set to zero nothing is deleted.
+func (m *Manager) Prune() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg.KeepHourly == 0 && m.cfg.KeepDaily == 0 && m.cfg.KeepWeekly == 0 {
+		return nil, nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := retained(backups, m.cfg.KeepHourly, m.cfg.KeepDaily, m.cfg.KeepWeekly)
+
+	var removed []string
+	for _, b := range backups {
+		if keep[b.Name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.cfg.Dir, b.Name)); err != nil {
+			return removed, fmt.Errorf("failed to remove backup %s: %w", b.Name, err)
+		}
+		removed = append(removed, b.Name)
+	}
+	return removed, nil
+}
+
+// retained selects the backups to keep. backups must be sorted newest first.
+func retained(backups []Info, hourly, daily, weekly int) map[string]bool {
+	keep := make(map[string]bool)
+	if len(backups) > 0 {
+		keep[backups[0].Name] = true
+	}
+
+	tiers := []struct {
+		limit  int
+		bucket func(time.Time) string
+	}{
+		{hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+	}
+
+	for _, tier := range tiers {
+		seen := make(map[string]bool)
+		for _, b := range backups {
+			if len(seen) >= tier.limit {
+				break
+			}
+			key := tier.bucket(b.CreatedAt.UTC())
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keep[b.Name] = true
+		}
+	}
+
+	return keep
+}
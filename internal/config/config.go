@@ -12,28 +12,153 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
+	Password PasswordConfig `mapstructure:"password"`
+	Security SecurityConfig `mapstructure:"security"`
 	Upload   UploadConfig   `mapstructure:"upload"`
 	Blog     BlogConfig     `mapstructure:"blog"`
+	Log      LogConfig      `mapstructure:"log"`
+	Metrics  MetricsConfig  `mapstructure:"metrics"`
+	Tracing  TracingConfig  `mapstructure:"tracing"`
+	Health   HealthConfig   `mapstructure:"health"`
+	Backup   BackupConfig   `mapstructure:"backup"`
+
+	Maintenance MaintenanceConfig `mapstructure:"maintenance"`
+	Views       ViewsConfig       `mapstructure:"views"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
-	Mode string `mapstructure:"mode"` // development, production
+	Host            string        `mapstructure:"host"`
+	Port            int           `mapstructure:"port"`
+	Mode            string        `mapstructure:"mode"` // development, production
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	BodyLimit       int64         `mapstructure:"body_limit"`      // bytes; 0 derives it from upload.max_size
+	TrustedProxies  []string      `mapstructure:"trusted_proxies"` // IPs or CIDRs allowed to set proxy headers
+	ProxyHeader     string        `mapstructure:"proxy_header"`    // X-Forwarded-For, X-Real-IP or Forwarded
+	CORS            CORSConfig    `mapstructure:"cors"`
+	TLS             TLSConfig     `mapstructure:"tls"`
+}
+
+// Supported values of server.proxy_header
+const (
+	ProxyHeaderXForwardedFor = "X-Forwarded-For"
+	ProxyHeaderXRealIP       = "X-Real-IP"
+	ProxyHeaderForwarded     = "Forwarded"
+)
+
+// TLSConfig holds native HTTPS configuration
+type TLSConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	CertFile       string        `mapstructure:"cert_file"`
+	KeyFile        string        `mapstructure:"key_file"`
+	MinVersion     string        `mapstructure:"min_version"`     // "1.2" or "1.3"
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // how often to check the files for changes
+	RedirectHTTP   bool          `mapstructure:"redirect_http"`   // serve an HTTP listener redirecting to HTTPS
+	HTTPPort       int           `mapstructure:"http_port"`
+}
+
+// CORSConfig holds cross-origin resource sharing configuration
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"` // supports "https://*.example.com"
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAge           int      `mapstructure:"max_age"` // preflight cache in seconds
 }
 
 // DatabaseConfig holds database-related configuration
 type DatabaseConfig struct {
-	Driver string `mapstructure:"driver"`
-	Path   string `mapstructure:"path"`
+	Driver string `mapstructure:"driver"` // sqlite or postgres
+	Path   string `mapstructure:"path"`   // SQLite database file
+	DSN    string `mapstructure:"dsn"`    // PostgreSQL connection string
+
+	// Connection pool settings for PostgreSQL; SQLite pools are sized by
+	// SQLite.ReadConns
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+
+	SQLite SQLiteConfig `mapstructure:"sqlite"`
+}
+
+// SQLiteConfig holds SQLite connection tuning. Writes go through a single
+// connection while reads use a separate pool, which WAL mode lets run
+// alongside the writer.
+type SQLiteConfig struct {
+	ReadConns   int           `mapstructure:"read_conns"`   // size of the read-only pool
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"` // how long to wait for a lock
+	Synchronous string        `mapstructure:"synchronous"`  // OFF, NORMAL, FULL or EXTRA
+	CacheSize   int           `mapstructure:"cache_size"`   // pages if positive, KiB if negative
+	MmapSize    int64         `mapstructure:"mmap_size"`    // bytes of memory-mapped I/O, 0 disables
+	TempStore   string        `mapstructure:"temp_store"`   // DEFAULT, FILE or MEMORY
 }
 
+// Supported values of database.driver
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
 // JWTConfig holds JWT-related configuration
 type JWTConfig struct {
 	Secret          string        `mapstructure:"secret"`
 	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	Cookie          CookieConfig  `mapstructure:"cookie"`
+}
+
+// CookieConfig holds cookie-based authentication configuration
+type CookieConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	AccessToken bool   `mapstructure:"access_token"` // also store the access token in a cookie
+	Domain      string `mapstructure:"domain"`
+	Secure      bool   `mapstructure:"secure"`
+	SameSite    string `mapstructure:"same_site"` // Strict, Lax, None
+}
+
+// PasswordConfig holds password policy and hashing configuration
+type PasswordConfig struct {
+	MinLength        int  `mapstructure:"min_length"`
+	MaxLength        int  `mapstructure:"max_length"`
+	RequireUppercase bool `mapstructure:"require_uppercase"`
+	RequireLowercase bool `mapstructure:"require_lowercase"`
+	RequireDigit     bool `mapstructure:"require_digit"`
+	RequireSymbol    bool `mapstructure:"require_symbol"`
+	RejectCommon     bool `mapstructure:"reject_common"`
+	RejectPersonal   bool `mapstructure:"reject_personal"`
+	BcryptCost       int  `mapstructure:"bcrypt_cost"`
+}
+
+// SecurityConfig holds HTTP security header configuration
+type SecurityConfig struct {
+	ContentTypeNosniff bool       `mapstructure:"content_type_nosniff"`
+	FrameOptions       string     `mapstructure:"frame_options"`
+	XSSProtection      string     `mapstructure:"xss_protection"`
+	ReferrerPolicy     string     `mapstructure:"referrer_policy"`
+	PermissionsPolicy  string     `mapstructure:"permissions_policy"`
+	HSTS               HSTSConfig `mapstructure:"hsts"`
+	CSP                CSPConfig  `mapstructure:"csp"`
+}
+
+// HSTSConfig holds Strict-Transport-Security configuration (production only)
+type HSTSConfig struct {
+	MaxAge            int  `mapstructure:"max_age"` // seconds, 0 disables
+	IncludeSubdomains bool `mapstructure:"include_subdomains"`
+	Preload           bool `mapstructure:"preload"`
+}
+
+// CSPConfig holds Content-Security-Policy configuration
+type CSPConfig struct {
+	Enabled    bool                `mapstructure:"enabled"`
+	ReportOnly bool                `mapstructure:"report_only"`
+	UseNonce   bool                `mapstructure:"use_nonce"` // per-request nonce for inline scripts
+	ReportURI  string              `mapstructure:"report_uri"`
+	Directives map[string][]string `mapstructure:"directives"`
 }
 
 // UploadConfig holds file upload configuration
@@ -52,8 +177,72 @@ type BlogConfig struct {
 	PostsPerPage int    `mapstructure:"posts_per_page"`
 }
 
+// LogConfig holds logging configuration
+type LogConfig struct {
+	Level  string `mapstructure:"level"`  // debug, info, warn, error
+	Format string `mapstructure:"format"` // text, json
+}
+
+// MetricsConfig holds Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Token   string `mapstructure:"token"` // optional bearer token required to scrape
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"` // otlp, stdout
+	Endpoint    string  `mapstructure:"endpoint"` // OTLP/HTTP collector host:port
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// HealthConfig holds readiness check configuration
+type HealthConfig struct {
+	CheckTimeout time.Duration `mapstructure:"check_timeout"`
+	MinFreeDisk  uint64        `mapstructure:"min_free_disk"` // bytes
+}
+
+// BackupConfig holds scheduled backup configuration
+type BackupConfig struct {
+	Enabled        bool          `mapstructure:"enabled"` // run scheduled backups
+	Dir            string        `mapstructure:"dir"`
+	Interval       time.Duration `mapstructure:"interval"`
+	IncludeUploads bool          `mapstructure:"include_uploads"`
+	KeepHourly     int           `mapstructure:"keep_hourly"` // newest backup of each of the last N hours
+	KeepDaily      int           `mapstructure:"keep_daily"`
+	KeepWeekly     int           `mapstructure:"keep_weekly"`
+
+	// DownloadTimeout replaces server.write_timeout for archive downloads
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+}
+
+// MaintenanceConfig holds scheduled database maintenance configuration
+type MaintenanceConfig struct {
+	Enabled       bool          `mapstructure:"enabled"` // run maintenance on a schedule
+	Interval      time.Duration `mapstructure:"interval"`
+	SpamRetention time.Duration `mapstructure:"spam_retention"` // delete spam comments older than this, 0 keeps them
+}
+
+// ViewsConfig holds post view counting configuration
+type ViewsConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // how often counts are written to the database
+	MaxVisitors   int           `mapstructure:"max_visitors"`   // distinct visitor/post pairs remembered per day
+}
+
 // Load reads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
+	_, cfg, err := load(configPath)
+	return cfg, err
+}
+
+// load reads and validates the configuration, returning the viper instance
+// it was read with so the file can be watched
+func load(configPath string) (*viper.Viper, *Config, error) {
 	v := viper.New()
 
 	// Set default values
@@ -69,24 +258,36 @@ func Load(configPath string) (*Config, error) {
 		v.AddConfigPath("./config")
 	}
 
-	// Read environment variables
-	v.AutomaticEnv()
+	// Read environment variables (BYTECABINET_SERVER_PORT, ..._FILE)
+	if err := bindEnv(v); err != nil {
+		return nil, nil, err
+	}
 
 	// Read config file
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+			return nil, nil, fmt.Errorf("failed to read config file: %w", err)
 		}
 		// Config file not found, use defaults and env vars
 	}
 
+	cfg, err := decode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, cfg, nil
+}
+
+// decode unmarshals and validates the configuration held by v
+func decode(v *viper.Viper) (*Config, error) {
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// Validate required fields
-	if err := validate(&cfg); err != nil {
+	// Validate the whole configuration
+	if err := Validate(&cfg); err != nil {
 		return nil, err
 	}
 
@@ -99,15 +300,83 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.host", "0.0.0.0")
 	v.SetDefault("server.port", 3000)
 	v.SetDefault("server.mode", "development")
+	v.SetDefault("server.read_timeout", "15s")
+	v.SetDefault("server.write_timeout", "30s")
+	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.shutdown_timeout", "15s")
+	v.SetDefault("server.body_limit", 0)
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.proxy_header", "")
+	v.SetDefault("server.tls.enabled", false)
+	v.SetDefault("server.tls.cert_file", "")
+	v.SetDefault("server.tls.key_file", "")
+	v.SetDefault("server.tls.min_version", "1.2")
+	v.SetDefault("server.tls.reload_interval", "1m")
+	v.SetDefault("server.tls.redirect_http", false)
+	v.SetDefault("server.tls.http_port", 80)
+	v.SetDefault("server.cors.allowed_origins", []string{}) // empty: any origin in development, none otherwise
+	v.SetDefault("server.cors.allowed_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	v.SetDefault("server.cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"})
+	v.SetDefault("server.cors.exposed_headers", []string{"X-CSRF-Token", "X-Request-ID"})
+	v.SetDefault("server.cors.allow_credentials", false)
+	v.SetDefault("server.cors.max_age", 600)
 
 	// Database defaults
 	v.SetDefault("database.driver", "sqlite")
 	v.SetDefault("database.path", "./data/byte-cabinet.db")
+	v.SetDefault("database.dsn", "")
+	v.SetDefault("database.max_open_conns", 10)
+	v.SetDefault("database.max_idle_conns", 5)
+	v.SetDefault("database.conn_max_lifetime", "30m")
+	v.SetDefault("database.conn_max_idle_time", "5m")
+	v.SetDefault("database.sqlite.read_conns", 4)
+	v.SetDefault("database.sqlite.busy_timeout", "5s")
+	v.SetDefault("database.sqlite.synchronous", "NORMAL")
+	v.SetDefault("database.sqlite.cache_size", -20000)   // 20 MB per connection
+	v.SetDefault("database.sqlite.mmap_size", 268435456) // 256 MB
+	v.SetDefault("database.sqlite.temp_store", "MEMORY")
 
 	// JWT defaults
 	v.SetDefault("jwt.secret", "")
 	v.SetDefault("jwt.access_token_ttl", "15m")
 	v.SetDefault("jwt.refresh_token_ttl", "168h") // 7 days
+	v.SetDefault("jwt.cookie.enabled", false)
+	v.SetDefault("jwt.cookie.access_token", false)
+	v.SetDefault("jwt.cookie.domain", "")
+	v.SetDefault("jwt.cookie.secure", true)
+	v.SetDefault("jwt.cookie.same_site", "Strict")
+
+	// Password defaults
+	v.SetDefault("password.min_length", 8)
+	v.SetDefault("password.max_length", 72) // bcrypt input limit
+	v.SetDefault("password.require_uppercase", false)
+	v.SetDefault("password.require_lowercase", true)
+	v.SetDefault("password.require_digit", true)
+	v.SetDefault("password.require_symbol", false)
+	v.SetDefault("password.reject_common", true)
+	v.SetDefault("password.reject_personal", true)
+	v.SetDefault("password.bcrypt_cost", 12)
+
+	// Security header defaults
+	v.SetDefault("security.content_type_nosniff", true)
+	v.SetDefault("security.frame_options", "DENY")
+	v.SetDefault("security.xss_protection", "1; mode=block")
+	v.SetDefault("security.referrer_policy", "strict-origin-when-cross-origin")
+	v.SetDefault("security.permissions_policy", "camera=(), microphone=(), geolocation=(), payment=()")
+	v.SetDefault("security.hsts.max_age", 31536000) // 1 year
+	v.SetDefault("security.hsts.include_subdomains", true)
+	v.SetDefault("security.hsts.preload", false)
+	v.SetDefault("security.csp.enabled", true)
+	v.SetDefault("security.csp.report_only", false)
+	v.SetDefault("security.csp.use_nonce", false)
+	v.SetDefault("security.csp.report_uri", "")
+	v.SetDefault("security.csp.directives", map[string][]string{
+		"default-src":     {"'self'"},
+		"img-src":         {"'self'", "data:"},
+		"object-src":      {"'none'"},
+		"base-uri":        {"'self'"},
+		"frame-ancestors": {"'none'"},
+	})
 
 	// Upload defaults
 	v.SetDefault("upload.path", "./uploads")
@@ -125,14 +394,47 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("blog.author", "")
 	v.SetDefault("blog.url", "http://localhost:3000")
 	v.SetDefault("blog.posts_per_page", 10)
-}
 
-// validate checks required configuration values
-func validate(cfg *Config) error {
-	if cfg.JWT.Secret == "" {
-		return fmt.Errorf("jwt.secret is required")
-	}
-	return nil
+	// Log defaults
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "text")
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.token", "")
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.exporter", "otlp")
+	v.SetDefault("tracing.endpoint", "localhost:4318")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.service_name", "byte-cabinet")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Health defaults
+	v.SetDefault("health.check_timeout", "2s")
+	v.SetDefault("health.min_free_disk", 104857600) // 100MB
+
+	// Backup defaults
+	v.SetDefault("backup.enabled", false)
+	v.SetDefault("backup.dir", "./data/backups")
+	v.SetDefault("backup.interval", "1h")
+	v.SetDefault("backup.include_uploads", true)
+	v.SetDefault("backup.keep_hourly", 24)
+	v.SetDefault("backup.keep_daily", 7)
+	v.SetDefault("backup.keep_weekly", 4)
+	v.SetDefault("backup.download_timeout", "30m")
+
+	// Maintenance defaults
+	v.SetDefault("maintenance.enabled", true)
+	v.SetDefault("maintenance.interval", "24h")
+	v.SetDefault("maintenance.spam_retention", "720h") // 30 days
+
+	// View counting defaults
+	v.SetDefault("views.enabled", true)
+	v.SetDefault("views.flush_interval", "30s")
+	v.SetDefault("views.max_visitors", 100000)
 }
 
 // Address returns the server address in host:port format
@@ -140,6 +442,25 @@ func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// multipartOverhead is the headroom added to upload.max_size for the
+// multipart encoding and the other form fields of an upload request
+const multipartOverhead = 1 << 20
+
+// BodyLimit returns the maximum request body size in bytes. Unless set
+// explicitly it is derived from upload.max_size so uploads of the
+// configured size are accepted.
+func (c *Config) BodyLimit() int {
+	if c.Server.BodyLimit > 0 {
+		return int(c.Server.BodyLimit)
+	}
+	return int(c.Upload.MaxSize) + multipartOverhead
+}
+
+// HTTPAddress returns the address of the HTTP to HTTPS redirect listener
+func (c *ServerConfig) HTTPAddress() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.TLS.HTTPPort)
+}
+
 // IsDevelopment returns true if running in development mode
 func (c *ServerConfig) IsDevelopment() bool {
 	return c.Mode == "development"
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is prepended to environment variable names, e.g.
+// BYTECABINET_SERVER_PORT overrides server.port
+const EnvPrefix = "BYTECABINET"
+
+// fileSuffix marks variables holding the path of a file with the value,
+// e.g. BYTECABINET_JWT_SECRET_FILE=/run/secrets/jwt_secret
+const fileSuffix = "_FILE"
+
+// envKeyReplacer maps config keys to environment variable names
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// bindEnv maps every config key to its environment variable. Keys are
+// bound explicitly because viper only consults AutomaticEnv for keys it
+// already knows, which Unmarshal does not guarantee.
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+		if err := bindEnvFile(v, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindEnvFile sets key from the file named by its _FILE variable, which
+// is how Docker and Kubernetes secrets are usually mounted
+func bindEnvFile(v *viper.Viper, key string) error {
+	name := EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key)) + fileSuffix
+	path, ok := os.LookupEnv(name)
+	if !ok || path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	return nil
+}
+
+// configKeys lists the dotted keys of every leaf field of t. Map fields
+// cannot be expressed as a single variable and are skipped.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			if field.Type.PkgPath() == t.PkgPath() {
+				keys = append(keys, configKeys(field.Type, key)...)
+				continue
+			}
+		case reflect.Map:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
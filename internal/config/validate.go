@@ -0,0 +1,279 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// exampleJWTSecret is the placeholder secret shipped in config.example.yaml
+const exampleJWTSecret = "your-super-secret-key-change-in-production"
+
+// minProductionSecretLength is the minimum JWT secret length in production
+const minProductionSecretLength = 32
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// report collects validation problems
+type report struct {
+	problems []string
+}
+
+// addf records a problem
+func (r *report) addf(format string, args ...any) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+// check records a problem when ok is false
+func (r *report) check(ok bool, format string, args ...any) {
+	if !ok {
+		r.addf(format, args...)
+	}
+}
+
+// Validate checks the configuration and reports all problems at once
+func Validate(cfg *Config) error {
+	r := &report{}
+
+	validateServer(r, &cfg.Server)
+	validateJWT(r, cfg)
+	validatePassword(r, &cfg.Password)
+	validateDatabase(r, cfg)
+	validatePaths(r, cfg)
+
+	r.check(cfg.Upload.MaxSize > 0, "upload.max_size must be positive")
+	r.check(cfg.Blog.PostsPerPage > 0, "blog.posts_per_page must be positive")
+	r.check(oneOf(strings.ToLower(cfg.Log.Level), "debug", "info", "warn", "warning", "error"),
+		"log.level must be one of debug, info, warn, error")
+	r.check(oneOf(strings.ToLower(cfg.Log.Format), "text", "json"), "log.format must be text or json")
+	if cfg.Metrics.Enabled {
+		r.check(strings.HasPrefix(cfg.Metrics.Path, "/"), "metrics.path must start with /")
+		r.check(!cfg.Server.IsProduction() || cfg.Metrics.Token != "",
+			"metrics.token is required in production; set it or disable metrics")
+	}
+	if cfg.Tracing.Enabled {
+		r.check(oneOf(cfg.Tracing.Exporter, "otlp", "stdout"), "tracing.exporter must be otlp or stdout")
+		r.check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+	}
+	r.check(cfg.Health.CheckTimeout > 0, "health.check_timeout must be positive")
+	validateBackup(r, &cfg.Backup)
+	r.check(cfg.Maintenance.SpamRetention >= 0, "maintenance.spam_retention must not be negative")
+	r.check(!cfg.Maintenance.Enabled || cfg.Maintenance.Interval >= time.Minute, "maintenance.interval must be at least 1m")
+	if cfg.Views.Enabled {
+		r.check(cfg.Views.FlushInterval >= time.Second, "views.flush_interval must be at least 1s")
+		r.check(cfg.Views.MaxVisitors > 0, "views.max_visitors must be positive")
+	}
+
+	if len(r.problems) > 0 {
+		return &ValidationError{Problems: r.problems}
+	}
+	return nil
+}
+
+// validateServer checks the server section
+func validateServer(r *report, cfg *ServerConfig) {
+	r.check(cfg.Port > 0 && cfg.Port <= 65535, "server.port must be between 1 and 65535")
+	r.check(oneOf(cfg.Mode, "development", "production"), "server.mode must be development or production")
+	r.check(cfg.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	r.check(cfg.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	r.check(cfg.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	r.check(cfg.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	r.check(cfg.BodyLimit >= 0, "server.body_limit must not be negative")
+
+	r.check(oneOf(cfg.ProxyHeader, "", ProxyHeaderXForwardedFor, ProxyHeaderXRealIP, ProxyHeaderForwarded),
+		"server.proxy_header must be one of %s, %s, %s",
+		ProxyHeaderXForwardedFor, ProxyHeaderXRealIP, ProxyHeaderForwarded)
+	r.check(cfg.ProxyHeader == "" || len(cfg.TrustedProxies) > 0, "server.proxy_header requires server.trusted_proxies")
+	for _, proxy := range cfg.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				r.addf("server.trusted_proxies: invalid CIDR %q", proxy)
+			}
+		} else if net.ParseIP(proxy) == nil {
+			r.addf("server.trusted_proxies: invalid IP %q", proxy)
+		}
+	}
+
+	if tlsCfg := cfg.TLS; tlsCfg.Enabled {
+		r.check(tlsCfg.CertFile != "" && tlsCfg.KeyFile != "",
+			"server.tls.cert_file and server.tls.key_file are required when TLS is enabled")
+		r.check(oneOf(tlsCfg.MinVersion, "1.2", "1.3"), "server.tls.min_version must be 1.2 or 1.3")
+		r.check(tlsCfg.ReloadInterval > 0, "server.tls.reload_interval must be positive")
+		if tlsCfg.RedirectHTTP {
+			r.check(tlsCfg.HTTPPort > 0 && tlsCfg.HTTPPort <= 65535, "server.tls.http_port must be between 1 and 65535")
+			r.check(tlsCfg.HTTPPort != cfg.Port, "server.tls.http_port must differ from server.port")
+		}
+	}
+
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		switch {
+		case origin == "*":
+			r.check(!cfg.CORS.AllowCredentials, "server.cors.allow_credentials cannot be used with a \"*\" origin")
+		case !validOrigin(origin):
+			r.addf("server.cors.allowed_origins: invalid origin %q, expected scheme://host[:port]", origin)
+		}
+	}
+}
+
+// validateJWT checks token lifetimes, the secret and cookie settings
+func validateJWT(r *report, cfg *Config) {
+	jwt := cfg.JWT
+
+	switch {
+	case jwt.Secret == "":
+		r.addf("jwt.secret is required")
+	case cfg.Server.IsProduction() && jwt.Secret == exampleJWTSecret:
+		r.addf("jwt.secret must be changed from the example value in production")
+	case cfg.Server.IsProduction() && len(jwt.Secret) < minProductionSecretLength:
+		r.addf("jwt.secret must be at least %d characters in production", minProductionSecretLength)
+	}
+
+	r.check(jwt.AccessTokenTTL > 0, "jwt.access_token_ttl must be positive")
+	r.check(jwt.RefreshTokenTTL > 0, "jwt.refresh_token_ttl must be positive")
+	r.check(jwt.RefreshTokenTTL > jwt.AccessTokenTTL, "jwt.refresh_token_ttl must be greater than jwt.access_token_ttl")
+
+	switch jwt.Cookie.SameSite {
+	case "Strict", "Lax":
+	case "None":
+		r.check(jwt.Cookie.Secure, "jwt.cookie.same_site None requires jwt.cookie.secure")
+	default:
+		r.addf("jwt.cookie.same_site must be one of Strict, Lax, None")
+	}
+}
+
+// validatePassword checks the password policy and hashing cost
+func validatePassword(r *report, cfg *PasswordConfig) {
+	r.check(cfg.MinLength > 0, "password.min_length must be positive")
+	r.check(cfg.MaxLength >= cfg.MinLength, "password.max_length must not be less than password.min_length")
+	// bcrypt ignores everything past 72 bytes
+	r.check(cfg.MaxLength <= 72, "password.max_length must not exceed 72")
+	r.check(cfg.BcryptCost >= 4 && cfg.BcryptCost <= 31, "password.bcrypt_cost must be between 4 and 31")
+}
+
+// validateBackup checks the backup schedule and retention
+func validateBackup(r *report, cfg *BackupConfig) {
+	r.check(cfg.KeepHourly >= 0 && cfg.KeepDaily >= 0 && cfg.KeepWeekly >= 0,
+		"backup.keep_hourly, keep_daily and keep_weekly must not be negative")
+	r.check(cfg.DownloadTimeout > 0, "backup.download_timeout must be positive")
+	if !cfg.Enabled {
+		return
+	}
+	r.check(cfg.Interval >= time.Minute, "backup.interval must be at least 1m")
+	if cfg.Dir == "" {
+		r.addf("backup.dir is required")
+	} else if err := checkWritable(cfg.Dir); err != nil {
+		r.addf("backup.dir: %v", err)
+	}
+}
+
+// validateDatabase checks the driver and its connection settings
+func validateDatabase(r *report, cfg *Config) {
+	db := &cfg.Database
+	switch db.Driver {
+	case DriverSQLite:
+		if db.Path == "" {
+			r.addf("database.path is required")
+		} else if err := checkWritable(filepath.Dir(db.Path)); err != nil {
+			r.addf("database.path: %v", err)
+		}
+		validateSQLite(r, &db.SQLite)
+	case DriverPostgres:
+		r.check(db.DSN != "", "database.dsn is required when database.driver is postgres")
+		r.check(db.MaxOpenConns >= 0 && db.MaxIdleConns >= 0,
+			"database.max_open_conns and max_idle_conns must not be negative")
+		r.check(db.MaxOpenConns == 0 || db.MaxIdleConns <= db.MaxOpenConns,
+			"database.max_idle_conns must not exceed max_open_conns")
+		r.check(db.ConnMaxLifetime >= 0 && db.ConnMaxIdleTime >= 0,
+			"database.conn_max_lifetime and conn_max_idle_time must not be negative")
+		// Backups snapshot the SQLite file; PostgreSQL has pg_dump
+		r.check(!cfg.Backup.Enabled, "backup.enabled requires database.driver sqlite; use pg_dump for postgres")
+	default:
+		r.addf("database.driver must be %s or %s", DriverSQLite, DriverPostgres)
+	}
+}
+
+// validateSQLite checks the SQLite pool size and pragmas
+func validateSQLite(r *report, cfg *SQLiteConfig) {
+	r.check(cfg.ReadConns >= 1, "database.sqlite.read_conns must be at least 1")
+	r.check(cfg.BusyTimeout >= 0, "database.sqlite.busy_timeout must not be negative")
+	r.check(cfg.MmapSize >= 0, "database.sqlite.mmap_size must not be negative")
+	r.check(cfg.Synchronous == "" || oneOf(strings.ToUpper(cfg.Synchronous), "OFF", "NORMAL", "FULL", "EXTRA"),
+		"database.sqlite.synchronous must be one of OFF, NORMAL, FULL, EXTRA")
+	r.check(cfg.TempStore == "" || oneOf(strings.ToUpper(cfg.TempStore), "DEFAULT", "FILE", "MEMORY"),
+		"database.sqlite.temp_store must be one of DEFAULT, FILE, MEMORY")
+}
+
+// validatePaths checks that the directories the server writes to are writable
+func validatePaths(r *report, cfg *Config) {
+	if cfg.Upload.Path == "" {
+		r.addf("upload.path is required")
+	} else if err := checkWritable(cfg.Upload.Path); err != nil {
+		r.addf("upload.path: %v", err)
+	}
+}
+
+// checkWritable reports whether dir is, or can be created as, a writable
+// directory. Missing directories are checked through their nearest existing
+// parent, without creating anything.
+func checkWritable(dir string) error {
+	dir = filepath.Clean(dir)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable", dir)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// validOrigin reports whether origin is a scheme://host[:port] origin, with
+// an optional "*." wildcard subdomain directly after the scheme
+func validOrigin(origin string) bool {
+	if i := strings.Index(origin, "://*."); i != -1 {
+		origin = origin[:i+3] + origin[i+5:]
+	}
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" || strings.Contains(u.Host, "*") {
+		return false
+	}
+	return (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == ""
+}
+
+// oneOf reports whether value equals one of the allowed values
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
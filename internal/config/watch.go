@@ -0,0 +1,99 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Manager holds the current configuration and applies changes to the
+// hot-reloadable fields when the config file changes.
+//
+// Only blog metadata, the log level and CORS settings are reloaded; changes
+// to any other field are ignored until the next restart.
+type Manager struct {
+	v       *viper.Viper
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(cfg *Config)
+}
+
+// NewManager loads the configuration like Load and returns a manager for it
+func NewManager(configPath string) (*Manager, error) {
+	v, cfg, err := load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{v: v}
+	m.current.Store(cfg)
+	return m, nil
+}
+
+// Config returns the current configuration. The returned value must not
+// be modified; it is replaced as a whole on reload.
+func (m *Manager) Config() *Config {
+	return m.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after
+// every successful reload
+func (m *Manager) Subscribe(fn func(cfg *Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Watch starts watching the config file for changes. It does nothing when
+// the configuration was loaded without a file.
+func (m *Manager) Watch() {
+	if m.v.ConfigFileUsed() == "" {
+		return
+	}
+	m.v.OnConfigChange(func(fsnotify.Event) {
+		m.reload()
+	})
+	m.v.WatchConfig()
+}
+
+// reload decodes the changed file and applies its hot-reloadable fields.
+// An invalid file is rejected as a whole and the current config is kept.
+func (m *Manager) reload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	loaded, err := decode(m.v)
+	if err != nil {
+		slog.Error("config reload rejected", slog.Any("error", err))
+		return
+	}
+
+	current := m.current.Load()
+	next := *current
+	next.Blog = loaded.Blog
+	next.Log.Level = loaded.Log.Level
+	next.Server.CORS = loaded.Server.CORS
+
+	// Anything still differing from the loaded file needs a restart
+	pending := *loaded
+	pending.Blog, pending.Log.Level, pending.Server.CORS = current.Blog, current.Log.Level, current.Server.CORS
+	if !reflect.DeepEqual(&pending, current) {
+		slog.Warn("config file changed fields that are only applied on restart")
+	}
+
+	if reflect.DeepEqual(&next, current) {
+		return
+	}
+
+	m.current.Store(&next)
+	slog.Info("configuration reloaded", slog.String("file", m.v.ConfigFileUsed()))
+
+	for _, fn := range m.subscribers {
+		fn(&next)
+	}
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// benchRows is the number of rows the benchmarks read from
+const benchRows = 1000
+
+// benchSQLite is the tuning the server uses by default
+var benchSQLite = config.SQLiteConfig{
+	ReadConns:   4,
+	BusyTimeout: 5 * time.Second,
+	Synchronous: "NORMAL",
+	CacheSize:   -20000,
+	MmapSize:    268435456,
+	TempStore:   "MEMORY",
+}
+
+// benchPools compares reads served by the single writer connection, as
+// before the pools were split, with reads served by the read-only pool
+var benchPools = []struct {
+	name   string
+	reader func(db *DB) *sqlx.DB
+}{
+	{"shared", (*DB).Writer},
+	{"split", (*DB).Reader},
+}
+
+// openBenchDB creates a database with a seeded table in a temporary
+// directory
+func openBenchDB(b *testing.B) *DB {
+	b.Helper()
+
+	db, err := NewSQLite(filepath.Join(b.TempDir(), "bench.db"), benchSQLite)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	err = db.WithTx(context.Background(), func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`CREATE TABLE bench (id INTEGER PRIMARY KEY, value TEXT NOT NULL)`); err != nil {
+			return err
+		}
+		for i := 1; i <= benchRows; i++ {
+			if _, err := tx.Exec(`INSERT INTO bench (id, value) VALUES (?, ?)`, i, fmt.Sprint("value-", i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+// benchRead looks up a random row
+func benchRead(b *testing.B, pool *sqlx.DB) {
+	var value string
+	if err := pool.Get(&value, `SELECT value FROM bench WHERE id = ?`, rand.IntN(benchRows)+1); err != nil {
+		b.Error(err)
+	}
+}
+
+// BenchmarkReads measures concurrent point reads
+func BenchmarkReads(b *testing.B) {
+	for _, p := range benchPools {
+		b.Run(p.name, func(b *testing.B) {
+			db := openBenchDB(b)
+			reader := p.reader(db)
+
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					benchRead(b, reader)
+				}
+			})
+		})
+	}
+}
+
+// benchWriteRows is the number of rows each write transaction of
+// BenchmarkMixed updates, similar to a batched view count flush
+const benchWriteRows = 20
+
+// BenchmarkMixed measures concurrent point reads with one write
+// transaction in ten, the writes always going through the writer pool
+func BenchmarkMixed(b *testing.B) {
+	for _, p := range benchPools {
+		b.Run(p.name, func(b *testing.B) {
+			db := openBenchDB(b)
+			reader := p.reader(db)
+
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%10 != 0 {
+						benchRead(b, reader)
+						continue
+					}
+					if err := db.WithTx(context.Background(), benchWrite); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// benchWrite updates benchWriteRows random rows
+func benchWrite(tx *sqlx.Tx) error {
+	for range benchWriteRows {
+		_, err := tx.Exec(`UPDATE bench SET value = ? WHERE id = ?`, "updated", rand.IntN(benchRows)+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,49 +1,125 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/migrations"
 
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
-	_ "modernc.org/sqlite"
 )
 
-// DB wraps sqlx.DB to provide additional functionality
+// DB wraps sqlx.DB to provide additional functionality. The embedded pool
+// is the writer; Reader returns the pool to use for queries that don't
+// modify data.
 type DB struct {
 	*sqlx.DB
-	migrationPath string
+	reader        *sqlx.DB // nil when reads share the writer pool
+	dialect       Dialect
+	migrationPath string // migration source URL; empty uses the embedded migrations
+
+	latestOnce    sync.Once
+	latestVersion uint
+	latestErr     error
 }
 
-// New creates a new database connection
-func New(dbPath string) (*DB, error) {
-	// Ensure the directory exists
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create database directory: %w", err)
+// MigrationStatus describes the applied schema version relative to the
+// migrations available to the binary
+type MigrationStatus struct {
+	Current uint `json:"current"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+}
+
+// Pending reports whether migrations remain to be applied
+func (s *MigrationStatus) Pending() bool {
+	return s.Current < s.Latest
+}
+
+// Open connects to the database selected by cfg.Driver
+func Open(cfg config.DatabaseConfig) (*DB, error) {
+	switch cfg.Driver {
+	case config.DriverSQLite, "":
+		return NewSQLite(cfg.Path, cfg.SQLite)
+	case config.DriverPostgres:
+		sqlDB, err := openPostgres(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return connect(sqlDB, nil, postgresDialect{})
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
 	}
+}
 
-	// Open database connection
-	// Using modernc.org/sqlite which is a pure Go SQLite implementation
-	db, err := sqlx.Connect("sqlite", dbPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+// New creates a new SQLite database connection with default tuning
+func New(dbPath string) (*DB, error) {
+	return NewSQLite(dbPath, config.SQLiteConfig{})
+}
+
+// NewSQLite creates a new SQLite database connection with separate writer
+// and reader pools
+func NewSQLite(dbPath string, cfg config.SQLiteConfig) (*DB, error) {
+	writer, reader, err := openSQLite(dbPath, cfg)
 	if err != nil {
+		return nil, err
+	}
+	return connect(writer, reader, sqliteDialect{})
+}
+
+// connect verifies the connections and wraps them for the dialect. reader
+// may be nil to serve reads from the writer pool.
+func connect(writer, reader *sql.DB, dialect Dialect) (*DB, error) {
+	db := &DB{DB: sqlx.NewDb(writer, dialect.DriverName()), dialect: dialect}
+	if reader != nil {
+		db.reader = sqlx.NewDb(reader, dialect.DriverName())
+	}
+	if err := db.HealthCheck(context.Background()); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return db, nil
+}
+
+// Reader returns the pool for read-only queries
+func (db *DB) Reader() *sqlx.DB {
+	if db.reader != nil {
+		return db.reader
+	}
+	return db.DB
+}
 
-	// Set connection pool settings
-	db.SetMaxOpenConns(1) // SQLite only supports one writer at a time
-	db.SetMaxIdleConns(1)
+// Writer returns the pool for statements that modify data
+func (db *DB) Writer() *sqlx.DB {
+	return db.DB
+}
+
+// Close closes both pools
+func (db *DB) Close() error {
+	var readErr error
+	if db.reader != nil {
+		readErr = db.reader.Close()
+	}
+	return errors.Join(db.DB.Close(), readErr)
+}
 
-	return &DB{
-		DB:            db,
-		migrationPath: "file://migrations",
-	}, nil
+// Dialect returns the dialect of the connected database
+func (db *DB) Dialect() Dialect {
+	return db.dialect
 }
 
-// NewWithMigrationPath creates a new database connection with custom migration path
+// NewWithMigrationPath creates a new database connection reading migrations
+// from a source URL such as "file://migrations" instead of the embedded ones
 func NewWithMigrationPath(dbPath, migrationPath string) (*DB, error) {
 	db, err := New(dbPath)
 	if err != nil {
@@ -55,70 +131,165 @@ func NewWithMigrationPath(dbPath, migrationPath string) (*DB, error) {
 
 // Migrate runs database migrations
 func (db *DB) Migrate() error {
+	return db.withMigrator(func(m *migrate.Migrate) error {
+		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+		return nil
+	})
+}
+
+// MigrateDown rolls back all migrations
+func (db *DB) MigrateDown() error {
+	return db.withMigrator(func(m *migrate.Migrate) error {
+		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
+			return fmt.Errorf("failed to rollback migrations: %w", err)
+		}
+		return nil
+	})
+}
+
+// MigrateSteps runs n migration steps (positive = up, negative = down)
+func (db *DB) MigrateSteps(n int) error {
+	return db.withMigrator(func(m *migrate.Migrate) error {
+		if err := m.Steps(n); err != nil && err != migrate.ErrNoChange {
+			return fmt.Errorf("failed to run migration steps: %w", err)
+		}
+		return nil
+	})
+}
+
+// withMigrator creates a migrator for this database and runs fn with it.
+// The migrator itself is not closed because that would close the database;
+// only the migration source is.
+func (db *DB) withMigrator(fn func(m *migrate.Migrate) error) error {
 	// Create migration driver
-	driver, err := sqlite.WithInstance(db.DB.DB, &sqlite.Config{})
+	driver, err := db.dialect.migrationDriver(db.DB.DB)
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	// Create migrator
-	m, err := migrate.NewWithDatabaseInstance(db.migrationPath, "sqlite", driver)
+	src, err := db.openSource()
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return err
 	}
+	defer src.Close()
 
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	// Create migrator
+	m, err := migrate.NewWithInstance("migrations", src, db.dialect.DriverName(), driver)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
 	}
 
-	return nil
+	return fn(m)
 }
 
-// MigrateDown rolls back all migrations
-func (db *DB) MigrateDown() error {
-	driver, err := sqlite.WithInstance(db.DB.DB, &sqlite.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+// openSource opens the configured migration source, by default the
+// dialect's migrations embedded in the binary
+func (db *DB) openSource() (source.Driver, error) {
+	var (
+		src source.Driver
+		err error
+	)
+	if db.migrationPath != "" {
+		src, err = source.Open(db.migrationPath)
+	} else {
+		src, err = iofs.New(migrations.FS, db.dialect.MigrationDir())
 	}
-
-	m, err := migrate.NewWithDatabaseInstance(db.migrationPath, "sqlite", driver)
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return nil, fmt.Errorf("failed to open migration source: %w", err)
 	}
+	return src, nil
+}
+
+// Reindex rebuilds all indexes and refreshes the query planner statistics
+func (db *DB) Reindex(ctx context.Context) error {
+	return db.dialect.reindex(ctx, db)
+}
 
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to rollback migrations: %w", err)
+// BackupTo writes a consistent snapshot of the database to path. It is
+// safe to run while the server is serving requests. The file must not exist.
+// Only SQLite supports it; other dialects return ErrUnsupported.
+func (db *DB) BackupTo(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
 	}
+	return db.dialect.backupTo(ctx, db, path)
+}
 
+// HealthCheck verifies the database connections are working
+func (db *DB) HealthCheck(ctx context.Context) error {
+	if err := db.DB.PingContext(ctx); err != nil {
+		return err
+	}
+	if db.reader != nil {
+		return db.reader.PingContext(ctx)
+	}
 	return nil
 }
 
-// MigrateSteps runs n migration steps (positive = up, negative = down)
-func (db *DB) MigrateSteps(n int) error {
-	driver, err := sqlite.WithInstance(db.DB.DB, &sqlite.Config{})
+// MigrationStatus reports the applied and latest available schema versions.
+// It reads the migrate bookkeeping table directly, so it is safe to call
+// frequently and never modifies the database.
+func (db *DB) MigrationStatus(ctx context.Context) (*MigrationStatus, error) {
+	latest, err := db.latestMigration()
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		return nil, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(db.migrationPath, "sqlite", driver)
-	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
-	}
+	status := &MigrationStatus{Latest: latest}
 
-	if err := m.Steps(n); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migration steps: %w", err)
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err = db.GetContext(ctx, &row, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if err != nil {
+		// A missing table or row means no migration has been applied yet
+		if errors.Is(err, sql.ErrNoRows) || db.dialect.isUndefinedTable(err) {
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to read migration version: %w", err)
 	}
 
-	return nil
+	status.Current = uint(row.Version)
+	status.Dirty = row.Dirty
+	return status, nil
 }
 
-// Close closes the database connection
-func (db *DB) Close() error {
-	return db.DB.Close()
-}
+// latestMigration returns the highest migration version in the source.
+// The source is embedded in the deployment and read only once.
+func (db *DB) latestMigration() (uint, error) {
+	db.latestOnce.Do(func() {
+		src, err := db.openSource()
+		if err != nil {
+			db.latestErr = err
+			return
+		}
+		defer src.Close()
+
+		version, err := src.First()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return
+			}
+			db.latestErr = fmt.Errorf("failed to read migration source: %w", err)
+			return
+		}
+
+		for {
+			next, err := src.Next(version)
+			if errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			if err != nil {
+				db.latestErr = fmt.Errorf("failed to read migration source: %w", err)
+				return
+			}
+			version = next
+		}
+		db.latestVersion = version
+	})
 
-// HealthCheck verifies the database connection is working
-func (db *DB) HealthCheck() error {
-	return db.DB.Ping()
+	return db.latestVersion, db.latestErr
 }
@@ -0,0 +1,140 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/database/dbtest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// minLatestVersion is the newest migration at the time of writing
+const minLatestVersion = 2
+
+// tables are created by the migrations and removed when rolling back
+var tables = []string{"users", "posts", "comments", "page_views_daily"}
+
+func TestMigrations(t *testing.T) {
+	for _, driver := range dbtest.Drivers {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+			db := dbtest.Open(t, driver)
+
+			status := migrationStatus(t, db)
+			if status.Current != 0 || status.Latest < minLatestVersion || !status.Pending() {
+				t.Fatalf("fresh database status = %+v", status)
+			}
+
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("migrate up: %v", err)
+			}
+			status = migrationStatus(t, db)
+			if status.Current != status.Latest || status.Dirty || status.Pending() {
+				t.Fatalf("migrated status = %+v", status)
+			}
+			assertTables(t, db, true)
+
+			// Running again is a no-op
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("second migrate up: %v", err)
+			}
+
+			if err := db.MigrateSteps(-1); err != nil {
+				t.Fatalf("migrate one step down: %v", err)
+			}
+			if got := migrationStatus(t, db).Current; got != status.Latest-1 {
+				t.Fatalf("version after one step down = %d, want %d", got, status.Latest-1)
+			}
+
+			if err := db.MigrateDown(); err != nil {
+				t.Fatalf("migrate down: %v", err)
+			}
+			assertTables(t, db, false)
+
+			// The down migrations must leave a schema the up migrations accept
+			if err := db.Migrate(); err != nil {
+				t.Fatalf("migrate up after down: %v", err)
+			}
+			assertTables(t, db, true)
+			if err := db.HealthCheck(ctx); err != nil {
+				t.Fatalf("health check: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		insert := db.Rebind(`INSERT INTO users (username, email, password_hash) VALUES (?, ?, ?)`)
+
+		err := db.WithTx(ctx, func(tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx, insert, "alice", "alice@example.com", "hash")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("committed transaction: %v", err)
+		}
+
+		errRollback := errors.New("rollback")
+		err = db.WithTx(ctx, func(tx *sqlx.Tx) error {
+			if _, err := tx.ExecContext(ctx, insert, "bob", "bob@example.com", "hash"); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("rolled back transaction returned %v, want %v", err, errRollback)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("panic was not re-raised")
+				}
+			}()
+			_ = db.WithTx(ctx, func(tx *sqlx.Tx) error {
+				if _, err := tx.ExecContext(ctx, insert, "carol", "carol@example.com", "hash"); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+
+		var usernames []string
+		if err := db.Reader().SelectContext(ctx, &usernames, `SELECT username FROM users WHERE username IN ('alice', 'bob', 'carol')`); err != nil {
+			t.Fatal(err)
+		}
+		if len(usernames) != 1 || usernames[0] != "alice" {
+			t.Fatalf("users = %v, want only alice", usernames)
+		}
+	})
+}
+
+// migrationStatus returns the schema version of db
+func migrationStatus(t *testing.T, db *database.DB) *database.MigrationStatus {
+	t.Helper()
+	status, err := db.MigrationStatus(context.Background())
+	if err != nil {
+		t.Fatalf("migration status: %v", err)
+	}
+	return status
+}
+
+// assertTables checks whether the migrated tables exist
+func assertTables(t *testing.T, db *database.DB, exist bool) {
+	t.Helper()
+	for _, table := range tables {
+		var n int
+		err := db.Get(&n, `SELECT COUNT(*) FROM `+table)
+		if exist && err != nil {
+			t.Errorf("table %s: %v", table, err)
+		}
+		if !exist && err == nil {
+			t.Errorf("table %s still exists", table)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/XSAM/otelsql"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrUnsupported is returned for operations the database dialect does not
+// provide, such as online backups on PostgreSQL
+var ErrUnsupported = errors.New("not supported by this database")
+
+// Dialect hides the differences between the supported database engines.
+// Queries are written with ? placeholders and rebound by sqlx based on
+// DriverName, so repositories stay dialect-agnostic.
+type Dialect interface {
+	// DriverName is the database/sql driver name, which also selects the
+	// sqlx bind type
+	DriverName() string
+
+	// MigrationDir is the directory of the embedded migration set
+	MigrationDir() string
+
+	// migrationDriver wraps an open connection for golang-migrate
+	migrationDriver(db *sql.DB) (migratedb.Driver, error)
+
+	// reindex rebuilds indexes and refreshes planner statistics
+	reindex(ctx context.Context, db *DB) error
+
+	// backupTo writes a consistent snapshot of the database to path
+	backupTo(ctx context.Context, db *DB, path string) error
+
+	// isUndefinedTable reports whether err means a table does not exist
+	isUndefinedTable(err error) bool
+
+	// isRetryable reports whether a transaction failed only because of
+	// contention and can be run again
+	isRetryable(err error) bool
+
+	// isUniqueViolation reports whether err is a unique constraint violation
+	isUniqueViolation(err error) bool
+}
+
+// IsUniqueViolation reports whether err is a unique constraint violation
+// from any supported database. Repositories hold plain connections rather
+// than the DB, so this checks every dialect.
+func IsUniqueViolation(err error) bool {
+	return sqliteDialect{}.isUniqueViolation(err) || postgresDialect{}.isUniqueViolation(err)
+}
+
+// openTraced opens a database connection wrapped so statements show up as
+// child spans of the request trace
+func openTraced(driverName, dsn string, system attribute.KeyValue) (*sql.DB, error) {
+	sqlDB, err := otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+			SpanFilter:           hasParentSpan,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return sqlDB, nil
+}
+
+// hasParentSpan limits SQL spans to statements issued within a traced
+// operation, so migrations and background scrapes don't create root spans
+func hasParentSpan(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
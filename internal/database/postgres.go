@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// SQLSTATE codes checked by the dialect
+const (
+	pqUndefinedTable       = "42P01"
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+	pqUniqueViolation      = "23505"
+)
+
+// postgresDialect uses lib/pq, the driver golang-migrate's postgres
+// support is built on
+type postgresDialect struct{}
+
+func (postgresDialect) DriverName() string   { return "postgres" }
+func (postgresDialect) MigrationDir() string { return "postgres" }
+
+// openPostgres connects using the DSN and applies the pool settings
+func openPostgres(cfg config.DatabaseConfig) (*sql.DB, error) {
+	sqlDB, err := openTraced("postgres", cfg.DSN, semconv.DBSystemNamePostgreSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return sqlDB, nil
+}
+
+func (postgresDialect) migrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return postgres.WithInstance(db, &postgres.Config{})
+}
+
+func (postgresDialect) reindex(ctx context.Context, db *DB) error {
+	var name string
+	if err := db.GetContext(ctx, &name, `SELECT current_database()`); err != nil {
+		return fmt.Errorf("failed to read database name: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `REINDEX DATABASE `+pq.QuoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to rebuild indexes: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `ANALYZE`); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+	return nil
+}
+
+// backupTo is not provided; PostgreSQL deployments should use pg_dump or
+// their provider's snapshots
+func (postgresDialect) backupTo(context.Context, *DB, string) error {
+	return fmt.Errorf("online backup: %w; use pg_dump", ErrUnsupported)
+}
+
+func (postgresDialect) isUndefinedTable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUndefinedTable
+}
+
+func (postgresDialect) isRetryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) &&
+		(pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected)
+}
+
+func (postgresDialect) isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteDialect uses modernc.org/sqlite, a pure Go SQLite implementation
+type sqliteDialect struct{}
+
+func (sqliteDialect) DriverName() string   { return "sqlite" }
+func (sqliteDialect) MigrationDir() string { return "sqlite" }
+
+// openSQLite opens the database file, creating its directory if needed.
+// It returns a single-connection writer pool and a read-only pool: in WAL
+// mode readers never block the writer or each other, so only writes need
+// to be serialized.
+func openSQLite(dbPath string, cfg config.SQLiteConfig) (writer, reader *sql.DB, err error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// The writer is opened first so the database is switched to WAL mode
+	// before any reader connects
+	writer, err = openTraced("sqlite", sqliteDSN(dbPath, cfg, false), semconv.DBSystemNameSQLite)
+	if err != nil {
+		return nil, nil, err
+	}
+	writer.SetMaxOpenConns(1) // SQLite only supports one writer at a time
+	writer.SetMaxIdleConns(1)
+
+	reader, err = openTraced("sqlite", sqliteDSN(dbPath, cfg, true), semconv.DBSystemNameSQLite)
+	if err != nil {
+		writer.Close()
+		return nil, nil, err
+	}
+	readConns := max(cfg.ReadConns, 1)
+	reader.SetMaxOpenConns(readConns)
+	reader.SetMaxIdleConns(readConns)
+
+	return writer, reader, nil
+}
+
+// sqliteDSN builds the connection string with the configured pragmas.
+// Write transactions take the lock up front so they wait on busy_timeout
+// instead of failing when another process holds it.
+func sqliteDSN(dbPath string, cfg config.SQLiteConfig, readOnly bool) string {
+	pragmas := []string{"foreign_keys(1)"}
+	if !readOnly {
+		// auto_vacuum only takes effect on a new database, letting the
+		// maintenance job reclaim free pages incrementally
+		pragmas = append(pragmas, "auto_vacuum(INCREMENTAL)", "journal_mode(WAL)")
+	}
+	if cfg.BusyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	}
+	if cfg.Synchronous != "" {
+		pragmas = append(pragmas, fmt.Sprintf("synchronous(%s)", strings.ToUpper(cfg.Synchronous)))
+	}
+	if cfg.CacheSize != 0 {
+		pragmas = append(pragmas, fmt.Sprintf("cache_size(%d)", cfg.CacheSize))
+	}
+	if cfg.MmapSize > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("mmap_size(%d)", cfg.MmapSize))
+	}
+	if cfg.TempStore != "" {
+		pragmas = append(pragmas, fmt.Sprintf("temp_store(%s)", strings.ToUpper(cfg.TempStore)))
+	}
+
+	params := url.Values{}
+	if readOnly {
+		pragmas = append(pragmas, "query_only(1)")
+	} else {
+		params.Set("_txlock", "immediate")
+	}
+	params["_pragma"] = pragmas
+
+	return dbPath + "?" + params.Encode()
+}
+
+func (sqliteDialect) migrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return migratesqlite.WithInstance(db, &migratesqlite.Config{})
+}
+
+func (sqliteDialect) reindex(ctx context.Context, db *DB) error {
+	if _, err := db.ExecContext(ctx, `REINDEX`); err != nil {
+		return fmt.Errorf("failed to rebuild indexes: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `ANALYZE`); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+	return nil
+}
+
+func (sqliteDialect) backupTo(ctx context.Context, db *DB, path string) error {
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+func (sqliteDialect) isUndefinedTable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such table")
+}
+
+// isRetryable matches SQLITE_BUSY and SQLITE_LOCKED, including their
+// extended codes, which occur when another process holds the lock for
+// longer than busy_timeout
+func (sqliteDialect) isRetryable(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+func (sqliteDialect) isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
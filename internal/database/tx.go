@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Retry policy for transactions that hit a locked database
+const (
+	txMaxAttempts  = 5
+	txRetryBackoff = 20 * time.Millisecond
+)
+
+// WithTx runs fn in a transaction on the writer pool. The transaction is
+// committed when fn returns nil and rolled back when it returns an error or
+// panics; the panic is then re-raised. If the database is locked by another
+// process (SQLITE_BUSY) or PostgreSQL aborts the transaction for a
+// serialization conflict, fn is run again in a new transaction, so it must
+// not have side effects outside the database.
+func (db *DB) WithTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	backoff := txRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := db.runTx(ctx, fn)
+		if err == nil || attempt == txMaxAttempts || !db.dialect.isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// IsRetryable reports whether err is a lock or serialization conflict that
+// may succeed when tried again later
+func (db *DB) IsRetryable(err error) bool {
+	return db.dialect.isRetryable(err)
+}
+
+// runTx runs fn in a single transaction
+func (db *DB) runTx(ctx context.Context, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -1,25 +1,41 @@
 package handler
 
 import (
+	"path"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/middleware"
 	"github.com/aliaxy/byte-cabinet/internal/model"
 	"github.com/aliaxy/byte-cabinet/internal/service"
+	"github.com/aliaxy/byte-cabinet/internal/validation"
 	"github.com/aliaxy/byte-cabinet/pkg/response"
+	"github.com/aliaxy/byte-cabinet/pkg/utils"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Errors shared by the handlers
+var (
+	errInvalidBody      = apperr.New(apperr.KindBadRequest, "Invalid request body")
+	errNotAuthenticated = apperr.New(apperr.KindUnauthorized, "Authentication required")
+)
+
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
 	authService *service.AuthService
 	validate    *validator.Validate
+	cookie      config.CookieConfig
 }
 
 // NewAuthHandler creates a new authentication handler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, cookie config.CookieConfig) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
-		validate:    validator.New(),
+		validate:    validation.Validator(),
+		cookie:      cookie,
 	}
 }
 
@@ -30,21 +46,25 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 	// Parse request body
 	if err := c.BodyParser(&req); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+		return errInvalidBody.WithCause(err)
 	}
 
 	// Validate request
 	if err := h.validate.Struct(&req); err != nil {
-		return response.ValidationError(c, "Username and password are required")
+		return apperr.Validation("Username and password are required", err)
 	}
 
 	// Attempt login
-	result, err := h.authService.Login(c.Context(), &req)
+	result, err := h.authService.Login(c.UserContext(), &req)
 	if err != nil {
-		if err == service.ErrInvalidCredentials {
-			return response.Unauthorized(c, "Invalid username or password")
+		return err
+	}
+
+	// In cookie mode the tokens are moved out of the response body
+	if h.cookie.Enabled {
+		if err := h.setAuthCookies(c, result.Tokens); err != nil {
+			return apperr.Internal(err)
 		}
-		return response.InternalError(c, "")
 	}
 
 	return response.OK(c, result)
@@ -54,7 +74,8 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 // POST /api/v1/auth/logout
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	// For JWT-based auth, logout is typically handled client-side
-	// by removing the token. This endpoint exists for consistency.
+	// by removing the token. In cookie mode the auth cookies are cleared.
+	h.clearAuthCookies(c)
 	return response.OKWithMessage(c, nil, "Logged out successfully")
 }
 
@@ -64,15 +85,12 @@ func (h *AuthHandler) Me(c *fiber.Ctx) error {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		return response.Unauthorized(c, "")
+		return errNotAuthenticated
 	}
 
-	user, err := h.authService.GetCurrentUser(c.Context(), userID)
+	user, err := h.authService.GetCurrentUser(c.UserContext(), userID)
 	if err != nil {
-		if err == service.ErrUserNotFound {
-			return response.NotFound(c, "User not found")
-		}
-		return response.InternalError(c, "")
+		return err
 	}
 
 	return response.OK(c, user)
@@ -84,32 +102,24 @@ func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 	// Get user ID from context
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		return response.Unauthorized(c, "")
+		return errNotAuthenticated
 	}
 
 	var req model.PasswordChange
 
 	// Parse request body
 	if err := c.BodyParser(&req); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+		return errInvalidBody.WithCause(err)
 	}
 
 	// Validate request
 	if err := h.validate.Struct(&req); err != nil {
-		return response.ValidationError(c, "Both old and new passwords are required")
+		return apperr.Validation("Both old and new passwords are required", err)
 	}
 
 	// Attempt password change
-	err := h.authService.ChangePassword(c.Context(), userID, &req)
-	if err != nil {
-		switch err {
-		case service.ErrUserNotFound:
-			return response.NotFound(c, "User not found")
-		case service.ErrInvalidOldPassword:
-			return response.BadRequest(c, "Current password is incorrect")
-		default:
-			return response.InternalError(c, "")
-		}
+	if err := h.authService.ChangePassword(c.UserContext(), userID, &req); err != nil {
+		return err
 	}
 
 	return response.OKWithMessage(c, nil, "Password changed successfully")
@@ -124,20 +134,33 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 
 	var req RefreshRequest
 
-	// Parse request body
-	if err := c.BodyParser(&req); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	// In cookie mode the browser sends the refresh token automatically
+	if h.cookie.Enabled {
+		req.RefreshToken = c.Cookies(middleware.RefreshTokenCookie)
 	}
 
-	// Validate request
-	if err := h.validate.Struct(&req); err != nil {
-		return response.ValidationError(c, "Refresh token is required")
+	if req.RefreshToken == "" {
+		// Parse request body
+		if err := c.BodyParser(&req); err != nil {
+			return errInvalidBody.WithCause(err)
+		}
+
+		// Validate request
+		if err := h.validate.Struct(&req); err != nil {
+			return apperr.Validation("Refresh token is required", err)
+		}
 	}
 
 	// Attempt token refresh
-	tokens, err := h.authService.RefreshTokens(c.Context(), req.RefreshToken)
+	tokens, err := h.authService.RefreshTokens(c.UserContext(), req.RefreshToken)
 	if err != nil {
-		return response.Unauthorized(c, "Invalid or expired refresh token")
+		return err
+	}
+
+	if h.cookie.Enabled {
+		if err := h.setAuthCookies(c, tokens); err != nil {
+			return apperr.Internal(err)
+		}
 	}
 
 	return response.OK(c, tokens)
@@ -149,33 +172,85 @@ func (h *AuthHandler) UpdateProfile(c *fiber.Ctx) error {
 	// Get user ID from context
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		return response.Unauthorized(c, "")
+		return errNotAuthenticated
 	}
 
 	var req model.UserUpdate
 
 	// Parse request body
 	if err := c.BodyParser(&req); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+		return errInvalidBody.WithCause(err)
 	}
 
 	// Validate request
 	if err := h.validate.Struct(&req); err != nil {
-		return response.ValidationError(c, "Invalid profile data")
+		return apperr.Validation("Invalid profile data", err)
 	}
 
 	// Update profile
-	user, err := h.authService.UpdateProfile(c.Context(), userID, &req)
+	user, err := h.authService.UpdateProfile(c.UserContext(), userID, &req)
 	if err != nil {
-		if err == service.ErrUserNotFound {
-			return response.NotFound(c, "User not found")
-		}
-		return response.InternalError(c, "")
+		return err
 	}
 
 	return response.OKWithMessage(c, user, "Profile updated successfully")
 }
 
+// setAuthCookies stores the tokens and a fresh CSRF token as cookies and
+// removes the cookie-delivered tokens from the response body
+func (h *AuthHandler) setAuthCookies(c *fiber.Ctx, tokens *utils.TokenPair) error {
+	csrfToken, err := middleware.NewCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	refreshExpires := now.Add(time.Duration(tokens.RefreshExpiresIn) * time.Second)
+
+	// The refresh token is only needed by the auth routes
+	c.Cookie(h.newCookie(middleware.RefreshTokenCookie, tokens.RefreshToken, h.authPath(c), refreshExpires, true))
+	tokens.RefreshToken = ""
+
+	if h.cookie.AccessToken {
+		accessExpires := now.Add(time.Duration(tokens.ExpiresIn) * time.Second)
+		c.Cookie(h.newCookie(middleware.AccessTokenCookie, tokens.AccessToken, "/", accessExpires, true))
+		tokens.AccessToken = ""
+	}
+
+	// The CSRF cookie must be readable by scripts for the double-submit check
+	c.Cookie(h.newCookie(middleware.CSRFCookie, csrfToken, "/", refreshExpires, false))
+	c.Set(middleware.CSRFHeader, csrfToken)
+
+	return nil
+}
+
+// clearAuthCookies expires all cookies set by setAuthCookies
+func (h *AuthHandler) clearAuthCookies(c *fiber.Ctx) {
+	expired := time.Unix(0, 0)
+	c.Cookie(h.newCookie(middleware.RefreshTokenCookie, "", h.authPath(c), expired, true))
+	c.Cookie(h.newCookie(middleware.AccessTokenCookie, "", "/", expired, true))
+	c.Cookie(h.newCookie(middleware.CSRFCookie, "", "/", expired, false))
+}
+
+// newCookie builds a cookie using the configured security attributes
+func (h *AuthHandler) newCookie(name, value, cookiePath string, expires time.Time, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cookiePath,
+		Domain:   h.cookie.Domain,
+		Expires:  expires,
+		Secure:   h.cookie.Secure,
+		HTTPOnly: httpOnly,
+		SameSite: h.cookie.SameSite,
+	}
+}
+
+// authPath returns the path prefix of the auth route group, e.g. /api/v1/auth
+func (h *AuthHandler) authPath(c *fiber.Ctx) string {
+	return path.Dir(c.Route().Path)
+}
+
 // RegisterRoutes registers all auth routes
 func (h *AuthHandler) RegisterRoutes(app fiber.Router, authMiddleware fiber.Handler) {
 	auth := app.Group("/auth")
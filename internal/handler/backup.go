@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/backup"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// errBackupNotFound is returned for unknown backup names
+	errBackupNotFound = apperr.New(apperr.KindNotFound, "Backup not found")
+	// errBackupUnsupported is returned when the database cannot be snapshotted
+	errBackupUnsupported = apperr.New(apperr.KindBadRequest, "Backups are only supported with the SQLite database")
+)
+
+// BackupHandler handles backup administration requests
+type BackupHandler struct {
+	manager *backup.Manager
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(manager *backup.Manager) *BackupHandler {
+	return &BackupHandler{manager: manager}
+}
+
+// List returns the available backups, newest first
+// GET /api/v1/admin/backups
+func (h *BackupHandler) List(c *fiber.Ctx) error {
+	backups, err := h.manager.List()
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return response.OK(c, backups)
+}
+
+// Create takes a backup immediately
+// POST /api/v1/admin/backups
+func (h *BackupHandler) Create(c *fiber.Ctx) error {
+	info, err := h.manager.Create(c.UserContext())
+	if err != nil {
+		if errors.Is(err, database.ErrUnsupported) {
+			return errBackupUnsupported.WithCause(err)
+		}
+		return apperr.Internal(err)
+	}
+	return response.Created(c, info)
+}
+
+// Download sends a backup archive
+// GET /api/v1/admin/backups/:name
+func (h *BackupHandler) Download(c *fiber.Ctx) error {
+	name := c.Params("name")
+	path, err := h.manager.Path(name)
+	if err != nil {
+		if errors.Is(err, backup.ErrNotFound) {
+			return errBackupNotFound
+		}
+		return apperr.Internal(err)
+	}
+	return c.Download(path, name)
+}
+
+// RegisterRoutes registers the backup routes; all require authentication
+func (h *BackupHandler) RegisterRoutes(app fiber.Router, authMiddleware fiber.Handler) {
+	backups := app.Group("/admin/backups", authMiddleware)
+
+	backups.Get("/", h.List)
+	backups.Post("/", h.Create)
+	backups.Get("/:name", h.Download)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/buildinfo"
+	"github.com/aliaxy/byte-cabinet/internal/health"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	checker *health.Checker
+	service func() string
+	started time.Time
+}
+
+// NewHealthHandler creates a new health handler. service is called on each
+// request so the reported name follows configuration reloads.
+func NewHealthHandler(checker *health.Checker, service func() string) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		service: service,
+		started: time.Now(),
+	}
+}
+
+// Live reports that the process is running without touching dependencies
+// GET /health/live
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return response.OK(c, fiber.Map{
+		"status":  "alive",
+		"service": h.service(),
+		"uptime":  time.Since(h.started).Round(time.Second).String(),
+		"build":   buildinfo.Get(),
+	})
+}
+
+// Ready runs all readiness checks and returns 503 if a critical one fails
+// GET /health/ready
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	report := h.checker.Run(c.UserContext())
+
+	data := fiber.Map{
+		"status":  report.Status,
+		"service": h.service(),
+		"checks":  report.Checks,
+		"build":   buildinfo.Get(),
+	}
+
+	if !report.Ready() {
+		return response.ErrorWithInfo(c, fiber.StatusServiceUnavailable, &response.ErrorInfo{
+			Code:    response.ErrCodeUnavailable,
+			Message: "One or more critical checks failed",
+		}, data)
+	}
+
+	return response.OK(c, data)
+}
+
+// RegisterRoutes registers the health routes
+func (h *HealthHandler) RegisterRoutes(app fiber.Router) {
+	hc := app.Group("/health")
+
+	// Plain /health is kept for existing probes and behaves like liveness
+	hc.Get("/", h.Live)
+	hc.Get("/live", h.Live)
+	hc.Get("/ready", h.Ready)
+}
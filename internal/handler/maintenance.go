@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/maintenance"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errNoMaintenanceReport is returned before the first maintenance run
+var errNoMaintenanceReport = apperr.New(apperr.KindNotFound, "Maintenance has not run yet")
+
+// MaintenanceHandler handles database maintenance requests
+type MaintenanceHandler struct {
+	manager *maintenance.Manager
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(manager *maintenance.Manager) *MaintenanceHandler {
+	return &MaintenanceHandler{manager: manager}
+}
+
+// Last returns the report of the latest maintenance run
+// GET /api/v1/admin/maintenance
+func (h *MaintenanceHandler) Last(c *fiber.Ctx) error {
+	report := h.manager.Last()
+	if report == nil {
+		return errNoMaintenanceReport
+	}
+	return response.OK(c, report)
+}
+
+// Run performs maintenance immediately and returns its report
+// POST /api/v1/admin/maintenance
+func (h *MaintenanceHandler) Run(c *fiber.Ctx) error {
+	return response.OK(c, h.manager.RunOnce(c.UserContext()))
+}
+
+// RegisterRoutes registers the maintenance routes; all require authentication
+func (h *MaintenanceHandler) RegisterRoutes(app fiber.Router, authMiddleware fiber.Handler) {
+	group := app.Group("/admin/maintenance", authMiddleware)
+
+	group.Get("/", h.Last)
+	group.Post("/", h.Run)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/aliaxy/byte-cabinet/internal/service"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatsHandler serves the admin analytics. All endpoints accept optional
+// from and to query parameters (YYYY-MM-DD, UTC, inclusive) defaulting to
+// the last 30 days.
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+// NewStatsHandler creates a new statistics handler
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// Overview returns daily views, devices, top referrers and top posts
+// GET /api/v1/admin/stats/overview
+func (h *StatsHandler) Overview(c *fiber.Ctx) error {
+	overview, err := h.statsService.Overview(c.UserContext(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return err
+	}
+	return response.OK(c, overview)
+}
+
+// Posts ranks posts by views; limit defaults to 20 and is capped at 100
+// GET /api/v1/admin/stats/posts
+func (h *StatsHandler) Posts(c *fiber.Ctx) error {
+	posts, err := h.statsService.Posts(c.UserContext(), c.Query("from"), c.Query("to"), c.QueryInt("limit"))
+	if err != nil {
+		return err
+	}
+	return response.OK(c, posts)
+}
+
+// Post returns the daily views, devices and referrers of one post
+// GET /api/v1/admin/stats/posts/:id
+func (h *StatsHandler) Post(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return errInvalidPostID
+	}
+
+	stats, err := h.statsService.Post(c.UserContext(), int64(id), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return err
+	}
+	return response.OK(c, stats)
+}
+
+// RegisterRoutes registers the statistics routes; all require authentication
+func (h *StatsHandler) RegisterRoutes(app fiber.Router, authMiddleware fiber.Handler) {
+	stats := app.Group("/admin/stats", authMiddleware)
+
+	stats.Get("/overview", h.Overview)
+	stats.Get("/posts", h.Posts)
+	stats.Get("/posts/:id", h.Post)
+}
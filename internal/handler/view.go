@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/validation"
+	"github.com/aliaxy/byte-cabinet/internal/views"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// errPostNotFound is returned for views of unknown or unpublished posts
+	errPostNotFound = apperr.New(apperr.KindNotFound, "Post not found")
+	// errInvalidPostID is returned when the post ID is not a positive integer
+	errInvalidPostID = apperr.New(apperr.KindBadRequest, "Invalid post ID")
+)
+
+// ViewHandler records post views
+type ViewHandler struct {
+	tracker  *views.Tracker
+	validate *validator.Validate
+}
+
+// NewViewHandler creates a new view handler
+func NewViewHandler(tracker *views.Tracker) *ViewHandler {
+	return &ViewHandler{
+		tracker:  tracker,
+		validate: validation.Validator(),
+	}
+}
+
+// Record counts a view of a published post. The frontend sends it when a
+// post is displayed, optionally with document.referrer in the body; repeat
+// views and bots are accepted but not counted.
+// POST /api/v1/posts/:id/view
+func (h *ViewHandler) Record(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return errInvalidPostID
+	}
+
+	var req model.ViewRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errInvalidBody.WithCause(err)
+		}
+		if err := h.validate.Struct(&req); err != nil {
+			return apperr.Validation("Invalid referrer", err)
+		}
+	}
+
+	_, err = h.tracker.Track(c.UserContext(), views.Visit{
+		PostID:    int64(id),
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Referrer:  req.Referrer,
+	})
+	if err != nil {
+		if errors.Is(err, views.ErrPostNotFound) {
+			return errPostNotFound
+		}
+		return apperr.Internal(err)
+	}
+	return response.NoContent(c)
+}
+
+// RegisterRoutes registers the view routes
+func (h *ViewHandler) RegisterRoutes(app fiber.Router) {
+	app.Post("/posts/:id/view", h.Record)
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+)
+
+// DatabaseCheck verifies the database answers a ping
+func DatabaseCheck(db *database.DB) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Run:      db.HealthCheck,
+	}
+}
+
+// MigrationCheck verifies the schema is up to date and not left dirty
+// by a failed migration
+func MigrationCheck(db *database.DB) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			status, err := db.MigrationStatus(ctx)
+			if err != nil {
+				return err
+			}
+			if status.Dirty {
+				return fmt.Errorf("schema version %d is dirty", status.Current)
+			}
+			if status.Pending() {
+				return fmt.Errorf("schema version %d is behind latest %d", status.Current, status.Latest)
+			}
+			return nil
+		},
+	}
+}
+
+// WritableDirCheck verifies dir exists and files can be created in it.
+// The check is served unauthenticated, so failures are logged in full
+// and reported without the path.
+func WritableDirCheck(name, dir string) Check {
+	return Check{
+		Name:     name,
+		Critical: true,
+		Run: func(ctx context.Context) error {
+			info, err := os.Stat(dir)
+			if err != nil {
+				slog.WarnContext(ctx, "health check failed", slog.String("check", name), slog.Any("error", err))
+				if errors.Is(err, fs.ErrNotExist) {
+					return errors.New("directory does not exist")
+				}
+				return errors.New("directory is not accessible")
+			}
+			if !info.IsDir() {
+				slog.WarnContext(ctx, "health check failed", slog.String("check", name),
+					slog.String("error", dir+" is not a directory"))
+				return errors.New("not a directory")
+			}
+
+			f, err := os.CreateTemp(dir, ".health-*")
+			if err == nil {
+				err = errors.Join(f.Close(), os.Remove(f.Name()))
+			}
+			if err != nil {
+				slog.WarnContext(ctx, "health check failed", slog.String("check", name), slog.Any("error", err))
+				return errors.New("directory is not writable")
+			}
+			return nil
+		},
+	}
+}
+
+// DiskSpaceCheck verifies the filesystem holding path has at least
+// minFree bytes available
+func DiskSpaceCheck(name, path string, minFree uint64) Check {
+	return Check{
+		Name:     name,
+		Critical: true,
+		Run: func(context.Context) error {
+			free, err := freeDiskSpace(path)
+			if errors.Is(err, errors.ErrUnsupported) {
+				// Nothing to measure, don't hold back readiness
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if free < minFree {
+				return fmt.Errorf("%d bytes free, below minimum of %d", free, minFree)
+			}
+			return nil
+		},
+	}
+}
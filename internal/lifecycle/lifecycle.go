@@ -0,0 +1,76 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Manager runs background workers and stops them in reverse start order,
+// so workers started later (which may depend on earlier ones) are drained
+// first
+type Manager struct {
+	mu      sync.Mutex
+	workers []*worker
+}
+
+// worker is a running background goroutine
+type worker struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates an empty manager
+func New() *Manager {
+	return &Manager{}
+}
+
+// Go starts run in a goroutine. The context passed to run is cancelled on
+// shutdown; run should flush any pending work and return promptly.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &worker{
+		name:   name,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.workers = append(m.workers, w)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(w.done)
+		run(ctx)
+	}()
+}
+
+// Shutdown stops all workers in reverse start order and waits for each to
+// return. It gives up when ctx is done and reports the workers still running.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	workers := m.workers
+	m.workers = nil
+	m.mu.Unlock()
+
+	for i := len(workers) - 1; i >= 0; i-- {
+		w := workers[i]
+		w.cancel()
+
+		select {
+		case <-w.done:
+			slog.Debug("worker stopped", slog.String("worker", w.name))
+		case <-ctx.Done():
+			var pending []string
+			for j := i; j >= 0; j-- {
+				workers[j].cancel()
+				pending = append(pending, workers[j].name)
+			}
+			return fmt.Errorf("workers did not stop in time: %v: %w", pending, ctx.Err())
+		}
+	}
+
+	return nil
+}
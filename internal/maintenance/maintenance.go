@@ -0,0 +1,203 @@
+// Package maintenance runs periodic database housekeeping: integrity and
+// foreign key checks, planner statistics, WAL checkpoints, reclaiming free
+// pages and purging stale data.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+)
+
+// Task outcomes
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// errSkipped marks a task that did not apply to this database
+var errSkipped = errors.New("skipped")
+
+// TaskResult is the outcome of a single maintenance task
+type TaskResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Detail     string  `json:"detail,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// Report is the outcome of one maintenance run
+type Report struct {
+	StartedAt  time.Time    `json:"started_at"`
+	DurationMS float64      `json:"duration_ms"`
+	OK         bool         `json:"ok"`
+	Tasks      []TaskResult `json:"tasks"`
+}
+
+// task is one maintenance step. It returns a human-readable detail, and
+// errSkipped when it does not apply.
+type task struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// Manager runs maintenance tasks and keeps the latest report
+type Manager struct {
+	db       *database.DB
+	cfg      config.MaintenanceConfig
+	comments *repository.CommentRepository
+	tasks    []task
+
+	runMu sync.Mutex // serialises runs
+
+	mu   sync.RWMutex
+	last *Report
+}
+
+// NewManager creates a maintenance manager for the database. SQLite gets
+// the full set of checks; PostgreSQL's autovacuum covers most of them, so
+// it only purges data and refreshes statistics.
+func NewManager(db *database.DB, cfg config.MaintenanceConfig) *Manager {
+	m := &Manager{
+		db:       db,
+		cfg:      cfg,
+		comments: repository.NewCommentRepository(db),
+	}
+
+	m.tasks = []task{{"purge_spam", m.purgeSpam}}
+	if db.Dialect().DriverName() == config.DriverSQLite {
+		m.tasks = append(m.tasks,
+			task{"integrity_check", m.integrityCheck},
+			task{"foreign_key_check", m.foreignKeyCheck},
+			task{"incremental_vacuum", m.incrementalVacuum},
+			task{"optimize", m.optimize},
+			task{"wal_checkpoint", m.walCheckpoint},
+		)
+	} else {
+		m.tasks = append(m.tasks, task{"analyze", m.analyze})
+	}
+	return m
+}
+
+// Last returns the report of the latest run, or nil if none has run yet
+func (m *Manager) Last() *Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.last
+}
+
+// RunOnce runs every task, logs the outcome and stores the report. A failed
+// task does not stop the others.
+func (m *Manager) RunOnce(ctx context.Context) *Report {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	report := &Report{StartedAt: time.Now().UTC(), OK: true}
+	for _, t := range m.tasks {
+		result := runTask(ctx, t)
+		if result.Status == StatusFailed {
+			report.OK = false
+		}
+		report.Tasks = append(report.Tasks, result)
+	}
+	report.DurationMS = milliseconds(time.Since(report.StartedAt))
+
+	logReport(ctx, report)
+
+	m.mu.Lock()
+	m.last = report
+	m.mu.Unlock()
+	return report
+}
+
+// Run performs maintenance on cfg.Interval until ctx is cancelled
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.RunOnce(ctx)
+		}
+	}
+}
+
+// runTask runs t and converts its outcome into a result
+func runTask(ctx context.Context, t task) TaskResult {
+	start := time.Now()
+	detail, err := t.run(ctx)
+
+	result := TaskResult{
+		Name:       t.name,
+		Status:     StatusOK,
+		Detail:     detail,
+		DurationMS: milliseconds(time.Since(start)),
+	}
+	switch {
+	case errors.Is(err, errSkipped):
+		result.Status = StatusSkipped
+	case err != nil:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// logReport writes one line per task, at warning level for failures
+func logReport(ctx context.Context, report *Report) {
+	for _, t := range report.Tasks {
+		level := slog.LevelInfo
+		attrs := []slog.Attr{
+			slog.String("task", t.Name),
+			slog.String("status", t.Status),
+			slog.String("detail", t.Detail),
+			slog.Float64("duration_ms", t.DurationMS),
+		}
+		if t.Status == StatusFailed {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error", t.Error))
+		}
+		slog.LogAttrs(ctx, level, "maintenance task finished", attrs...)
+	}
+	slog.InfoContext(ctx, "maintenance finished",
+		slog.Bool("ok", report.OK),
+		slog.Float64("duration_ms", report.DurationMS),
+	)
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// purgeSpam deletes spam comments older than the retention period
+func (m *Manager) purgeSpam(ctx context.Context) (string, error) {
+	if m.cfg.SpamRetention == 0 {
+		return "spam retention disabled", errSkipped
+	}
+	removed, err := m.comments.DeleteSpamBefore(ctx, time.Now().Add(-m.cfg.SpamRetention))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d spam comment(s)", removed), nil
+}
+
+// analyze refreshes the PostgreSQL planner statistics
+func (m *Manager) analyze(ctx context.Context) (string, error) {
+	if _, err := m.db.Writer().ExecContext(ctx, `ANALYZE`); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,58 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/database/dbtest"
+)
+
+func TestIncrementalVacuumReclaimsAllFreePages(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t, config.DriverSQLite)
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fill a few hundred pages, then free them
+	var authorID int64
+	if err := db.Get(&authorID, `SELECT id FROM users LIMIT 1`); err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Repeat("x", 8192)
+	for i := range 100 {
+		_, err := db.Exec(`INSERT INTO posts (title, slug, content, author_id) VALUES (?, ?, ?, ?)`,
+			"post", fmt.Sprintf("post-%d", i), content, authorID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`DELETE FROM posts`); err != nil {
+		t.Fatal(err)
+	}
+
+	var before int64
+	if err := db.Get(&before, `PRAGMA freelist_count`); err != nil {
+		t.Fatal(err)
+	}
+	if before < 100 {
+		t.Fatalf("freelist_count = %d after deleting, want at least 100", before)
+	}
+
+	m := NewManager(db, config.MaintenanceConfig{Interval: time.Hour})
+	if _, err := m.incrementalVacuum(ctx); err != nil {
+		t.Fatalf("incrementalVacuum: %v", err)
+	}
+
+	var after int64
+	if err := db.Get(&after, `PRAGMA freelist_count`); err != nil {
+		t.Fatal(err)
+	}
+	if after != 0 {
+		t.Fatalf("freelist_count = %d after incremental vacuum of %d pages, want 0", after, before)
+	}
+}
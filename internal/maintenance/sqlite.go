@@ -0,0 +1,129 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxProblems limits how many integrity problems are included in a report
+const maxProblems = 10
+
+// autoVacuumIncremental is the PRAGMA auto_vacuum value for incremental mode
+const autoVacuumIncremental = 2
+
+// integrityCheck verifies the database file structure. It runs on the read
+// pool so writes are not blocked while the whole file is scanned.
+func (m *Manager) integrityCheck(ctx context.Context) (string, error) {
+	var problems []string
+	query := fmt.Sprintf(`PRAGMA integrity_check(%d)`, maxProblems)
+	if err := sqlx.SelectContext(ctx, m.db.Reader(), &problems, query); err != nil {
+		return "", err
+	}
+	if len(problems) == 1 && problems[0] == "ok" {
+		return "ok", nil
+	}
+	return strings.Join(problems, "; "), fmt.Errorf("integrity check reported %d problem(s)", len(problems))
+}
+
+// foreignKeyCheck looks for rows that reference missing parents, which can
+// exist if foreign keys were ever disabled
+func (m *Manager) foreignKeyCheck(ctx context.Context) (string, error) {
+	rows, err := m.db.Reader().QueryxContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	violations := map[string]int{}
+	total := 0
+	for rows.Next() {
+		cols, err := rows.SliceScan()
+		if err != nil {
+			return "", err
+		}
+		// Columns are table, rowid, parent and foreign key index
+		violations[fmt.Sprintf("%s->%s", cols[0], cols[2])]++
+		total++
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if total == 0 {
+		return "no violations", nil
+	}
+	parts := make([]string, 0, len(violations))
+	for ref, n := range violations {
+		parts = append(parts, fmt.Sprintf("%s: %d", ref, n))
+	}
+	return strings.Join(parts, ", "), fmt.Errorf("found %d foreign key violation(s)", total)
+}
+
+// incrementalVacuum returns free pages to the file system. It only works
+// on databases created with incremental auto-vacuum; older ones need a
+// one-time VACUUM after setting it.
+func (m *Manager) incrementalVacuum(ctx context.Context) (string, error) {
+	writer := m.db.Writer()
+
+	var mode int
+	if err := writer.GetContext(ctx, &mode, `PRAGMA auto_vacuum`); err != nil {
+		return "", err
+	}
+	if mode != autoVacuumIncremental {
+		return "auto_vacuum is not incremental; run VACUUM once to enable it", errSkipped
+	}
+
+	var before, after int64
+	if err := writer.GetContext(ctx, &before, `PRAGMA freelist_count`); err != nil {
+		return "", err
+	}
+	if before == 0 {
+		return "no free pages", nil
+	}
+	// The pragma returns a row per freed page and only frees the next page
+	// when stepped, so the rows must be drained
+	rows, err := writer.QueryContext(ctx, `PRAGMA incremental_vacuum`)
+	if err != nil {
+		return "", err
+	}
+	for rows.Next() {
+	}
+	if err := rows.Close(); err != nil {
+		return "", err
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if err := writer.GetContext(ctx, &after, `PRAGMA freelist_count`); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reclaimed %d of %d free page(s)", before-after, before), nil
+}
+
+// optimize lets SQLite refresh statistics for tables that need it
+func (m *Manager) optimize(ctx context.Context) (string, error) {
+	if _, err := m.db.Writer().ExecContext(ctx, `PRAGMA optimize`); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// walCheckpoint copies the WAL into the database file and truncates it
+func (m *Manager) walCheckpoint(ctx context.Context) (string, error) {
+	var result struct {
+		Busy         int `db:"busy"`
+		Log          int `db:"log"`
+		Checkpointed int `db:"checkpointed"`
+	}
+	if err := m.db.Writer().GetContext(ctx, &result, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return "", err
+	}
+	if result.Busy != 0 {
+		return fmt.Sprintf("partial: %d of %d frame(s) checkpointed, readers still active",
+			result.Checkpointed, result.Log), nil
+	}
+	return fmt.Sprintf("%d frame(s) checkpointed", result.Checkpointed), nil
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectTimeout bounds the database queries run during a scrape
+const collectTimeout = 5 * time.Second
+
+// diskUsageTTL limits how often the upload directory is walked
+const diskUsageTTL = time.Minute
+
+// BusinessCollector exposes content and storage gauges computed at scrape time
+type BusinessCollector struct {
+	statsRepo  *repository.StatsRepository
+	uploadPath string
+
+	posts       *prometheus.Desc
+	comments    *prometheus.Desc
+	uploadBytes *prometheus.Desc
+	uploadFiles *prometheus.Desc
+
+	mu          sync.Mutex
+	diskBytes   int64
+	diskFiles   int64
+	diskChecked time.Time
+}
+
+// NewBusinessCollector creates a collector for blog content and upload usage
+func NewBusinessCollector(statsRepo *repository.StatsRepository, uploadPath string) *BusinessCollector {
+	return &BusinessCollector{
+		statsRepo:  statsRepo,
+		uploadPath: uploadPath,
+		posts: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "posts"),
+			"Number of posts by status.",
+			[]string{"status"}, nil,
+		),
+		comments: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "comments"),
+			"Number of comments by status.",
+			[]string{"status"}, nil,
+		),
+		uploadBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upload", "bytes"),
+			"Total size of files in the upload directory.",
+			nil, nil,
+		),
+		uploadFiles: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "upload", "files"),
+			"Number of files in the upload directory.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (bc *BusinessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bc.posts
+	ch <- bc.comments
+	ch <- bc.uploadBytes
+	ch <- bc.uploadFiles
+}
+
+// Collect implements prometheus.Collector
+func (bc *BusinessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	if counts, err := bc.statsRepo.CountPostsByStatus(ctx); err != nil {
+		slog.Warn("failed to collect post metrics", slog.Any("error", err))
+	} else {
+		statuses := []string{
+			string(model.PostStatusDraft),
+			string(model.PostStatusPublished),
+			string(model.PostStatusArchived),
+		}
+		collectStatusCounts(ch, bc.posts, statuses, counts)
+	}
+
+	if counts, err := bc.statsRepo.CountCommentsByStatus(ctx); err != nil {
+		slog.Warn("failed to collect comment metrics", slog.Any("error", err))
+	} else {
+		statuses := []string{
+			string(model.CommentStatusPending),
+			string(model.CommentStatusApproved),
+			string(model.CommentStatusSpam),
+		}
+		collectStatusCounts(ch, bc.comments, statuses, counts)
+	}
+
+	size, files := bc.diskUsage()
+	ch <- prometheus.MustNewConstMetric(bc.uploadBytes, prometheus.GaugeValue, float64(size))
+	ch <- prometheus.MustNewConstMetric(bc.uploadFiles, prometheus.GaugeValue, float64(files))
+}
+
+// collectStatusCounts emits one gauge per status, reporting zero for known
+// statuses without rows so the series never disappear
+func collectStatusCounts(ch chan<- prometheus.Metric, desc *prometheus.Desc, statuses []string, counts []model.StatusCount) {
+	values := make(map[string]int64, len(statuses))
+	for _, status := range statuses {
+		values[status] = 0
+	}
+	for _, sc := range counts {
+		values[sc.Status] = sc.Count
+	}
+	for status, count := range values {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
+
+// diskUsage returns the cached upload directory usage, refreshing it when stale
+func (bc *BusinessCollector) diskUsage() (int64, int64) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if time.Since(bc.diskChecked) < diskUsageTTL {
+		return bc.diskBytes, bc.diskFiles
+	}
+
+	var size, files int64
+	err := filepath.WalkDir(bc.uploadPath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	// A missing upload directory simply means nothing has been uploaded yet
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("failed to measure upload directory", slog.Any("error", err))
+		return bc.diskBytes, bc.diskFiles
+	}
+
+	bc.diskBytes, bc.diskFiles, bc.diskChecked = size, files, time.Now()
+	return size, files
+}
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every application metric
+const namespace = "bytecabinet"
+
+// unmatchedRoute labels requests that did not match any route, so that
+// arbitrary paths cannot blow up label cardinality
+const unmatchedRoute = "unmatched"
+
+// Metrics holds the Prometheus registry and HTTP collectors
+type Metrics struct {
+	registry        *prometheus.Registry
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	inFlight        prometheus.Gauge
+
+	routesOnce sync.Once
+	routes     map[string]struct{} // "METHOD /path" of every handler route
+}
+
+// New creates a registry with Go runtime, process, database pool and
+// HTTP collectors. Additional collectors can be added with Register.
+func New(db *sql.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests currently being served.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "main"),
+		m.requestsTotal,
+		m.requestDuration,
+		m.inFlight,
+	)
+
+	return m
+}
+
+// Register adds collectors to the registry
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Middleware records request counts and latencies.
+// It must run outside any middleware that converts errors into responses
+// so the final status code is observed.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// The error handler has not run yet, derive the status from the error
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		// When no route matched, Route() reports the last middleware instead
+		route := c.Route()
+		routePath := route.Path
+		if !m.isHandlerRoute(c.App(), route) {
+			routePath = unmatchedRoute
+		}
+
+		labels := prometheus.Labels{
+			"method": c.Method(),
+			"route":  routePath,
+			"status": strconv.Itoa(status),
+		}
+		m.requestsTotal.With(labels).Inc()
+		m.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// isHandlerRoute reports whether route is a registered endpoint rather than
+// middleware. Routes are indexed on first use, after registration is done.
+func (m *Metrics) isHandlerRoute(app *fiber.App, route *fiber.Route) bool {
+	m.routesOnce.Do(func() {
+		m.routes = make(map[string]struct{})
+		for _, r := range app.GetRoutes(true) {
+			m.routes[r.Method+" "+r.Path] = struct{}{}
+		}
+	})
+	_, ok := m.routes[route.Method+" "+route.Path]
+	return ok
+}
+
+// Handler serves the registry in Prometheus text format.
+// When token is non-empty, requests must send it as a bearer token.
+func (m *Metrics) Handler(token string) fiber.Handler {
+	promHandler := adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+
+	return func(c *fiber.Ctx) error {
+		if token != "" {
+			scheme, provided, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") ||
+				subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return c.SendStatus(fiber.StatusUnauthorized)
+			}
+		}
+		return promHandler(c)
+	}
+}
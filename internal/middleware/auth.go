@@ -1,56 +1,33 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
 	"github.com/aliaxy/byte-cabinet/pkg/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// AuthMiddleware creates a JWT authentication middleware
+// AuthMiddleware creates a JWT authentication middleware.
+// The access token is read from the Authorization header, falling back to
+// the access token cookie set in cookie-based auth mode.
 func AuthMiddleware(jwtManager *utils.JWTManager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get authorization header
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error": fiber.Map{
-					"code":    "UNAUTHORIZED",
-					"message": "Authorization header is required",
-				},
-			})
+		tokenString, message := extractToken(c)
+		if tokenString == "" {
+			return apperr.New(apperr.KindUnauthorized, message)
 		}
 
-		// Check Bearer prefix
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error": fiber.Map{
-					"code":    "UNAUTHORIZED",
-					"message": "Invalid authorization header format",
-				},
-			})
-		}
-
-		tokenString := parts[1]
-
 		// Validate access token
 		claims, err := jwtManager.ValidateAccessToken(tokenString)
 		if err != nil {
 			message := "Invalid token"
-			if err == utils.ErrExpiredToken {
+			if errors.Is(err, utils.ErrExpiredToken) {
 				message = "Token has expired"
 			}
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error": fiber.Map{
-					"code":    "UNAUTHORIZED",
-					"message": message,
-				},
-			})
+			return apperr.Wrap(err, apperr.KindUnauthorized, message)
 		}
 
 		// Store user info in context
@@ -65,17 +42,12 @@ func AuthMiddleware(jwtManager *utils.JWTManager) fiber.Handler {
 // but doesn't require authentication
 func OptionalAuthMiddleware(jwtManager *utils.JWTManager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
+		tokenString, _ := extractToken(c)
+		if tokenString == "" {
 			return c.Next()
 		}
 
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-			return c.Next()
-		}
-
-		claims, err := jwtManager.ValidateAccessToken(parts[1])
+		claims, err := jwtManager.ValidateAccessToken(tokenString)
 		if err != nil {
 			return c.Next()
 		}
@@ -87,6 +59,26 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager) fiber.Handler {
 	}
 }
 
+// extractToken returns the access token from the Authorization header or the
+// access token cookie. When no token is found, a reason is returned instead.
+func extractToken(c *fiber.Ctx) (string, string) {
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		if token := c.Cookies(AccessTokenCookie); token != "" {
+			return token, ""
+		}
+		return "", "Authorization header is required"
+	}
+
+	// Check Bearer prefix
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", "Invalid authorization header format"
+	}
+
+	return parts[1], ""
+}
+
 // GetUserID retrieves the user ID from the context
 func GetUserID(c *fiber.Ctx) (int64, bool) {
 	userID, ok := c.Locals("userID").(int64)
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORS creates the CORS middleware from the server configuration.
+// Origins may be exact ("https://example.com") or wildcard subdomain
+// patterns ("https://*.example.com"). When no origins are configured,
+// every origin is allowed in development and none in other modes.
+// Malformed origins are reported as an error rather than a panic, so a bad
+// config reload cannot bring the server down.
+func CORS(cfg *config.ServerConfig) (h fiber.Handler, err error) {
+	corsCfg := cfg.CORS
+
+	fiberCfg := cors.Config{
+		AllowMethods:     strings.Join(corsCfg.AllowedMethods, ","),
+		AllowHeaders:     strings.Join(corsCfg.AllowedHeaders, ","),
+		ExposeHeaders:    strings.Join(corsCfg.ExposedHeaders, ","),
+		AllowCredentials: corsCfg.AllowCredentials,
+		MaxAge:           corsCfg.MaxAge,
+	}
+
+	switch {
+	case len(corsCfg.AllowedOrigins) > 0:
+		fiberCfg.AllowOrigins = strings.Join(corsCfg.AllowedOrigins, ",")
+	case cfg.IsDevelopment() && !corsCfg.AllowCredentials:
+		fiberCfg.AllowOrigins = "*"
+	case cfg.IsDevelopment():
+		// Credentials forbid a literal "*", so reflect any origin instead
+		fiberCfg.AllowOriginsFunc = func(string) bool { return true }
+	default:
+		// Same-origin only
+		fiberCfg.AllowOriginsFunc = func(string) bool { return false }
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("invalid CORS configuration: %v", p)
+		}
+	}()
+	return cors.New(fiberCfg), nil
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sort"
+	"strings"
+)
+
+// cspNonceDirectives lists directives that receive the per-request nonce
+var cspNonceDirectives = []string{"script-src", "style-src"}
+
+// CSPBuilder assembles a Content-Security-Policy header value
+type CSPBuilder struct {
+	directives map[string][]string
+}
+
+// NewCSPBuilder creates an empty CSP builder
+func NewCSPBuilder() *CSPBuilder {
+	return &CSPBuilder{directives: make(map[string][]string)}
+}
+
+// Add appends sources to a directive, creating it if needed.
+// Directives without sources (e.g. upgrade-insecure-requests) are allowed.
+func (b *CSPBuilder) Add(directive string, sources ...string) *CSPBuilder {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	if directive == "" {
+		return b
+	}
+	b.directives[directive] = append(b.directives[directive], sources...)
+	return b
+}
+
+// Build renders the policy. When nonce is non-empty it is added as a
+// 'nonce-...' source to script-src and style-src; if a directive is missing,
+// it is derived from default-src so the nonce does not loosen the policy.
+func (b *CSPBuilder) Build(nonce string) string {
+	directives := make(map[string][]string, len(b.directives)+len(cspNonceDirectives))
+	for name, sources := range b.directives {
+		directives[name] = sources
+	}
+
+	if nonce != "" {
+		nonceSource := "'nonce-" + nonce + "'"
+		for _, name := range cspNonceDirectives {
+			sources, ok := directives[name]
+			if !ok {
+				// Only derive the directive when a fallback exists
+				fallback, hasDefault := directives["default-src"]
+				if !hasDefault {
+					continue
+				}
+				sources = fallback
+			}
+			directives[name] = append(append([]string{}, sources...), nonceSource)
+		}
+	}
+
+	// Keep output deterministic, with default-src leading
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "default-src" || names[j] == "default-src" {
+			return names[i] == "default-src"
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if sources := directives[name]; len(sources) > 0 {
+			parts = append(parts, name+" "+strings.Join(sources, " "))
+		} else {
+			parts = append(parts, name)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// generateNonce returns a random base64 encoded nonce
+func generateNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
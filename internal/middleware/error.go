@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/validation"
+	"github.com/aliaxy/byte-cabinet/pkg/logger"
+	"github.com/aliaxy/byte-cabinet/pkg/response"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// kindMapping maps an error kind to its HTTP status and default error code
+var kindMapping = map[apperr.Kind]struct {
+	status int
+	code   string
+}{
+	apperr.KindBadRequest:   {fiber.StatusBadRequest, response.ErrCodeBadRequest},
+	apperr.KindValidation:   {fiber.StatusBadRequest, response.ErrCodeValidation},
+	apperr.KindUnauthorized: {fiber.StatusUnauthorized, response.ErrCodeUnauthorized},
+	apperr.KindForbidden:    {fiber.StatusForbidden, response.ErrCodeForbidden},
+	apperr.KindNotFound:     {fiber.StatusNotFound, response.ErrCodeNotFound},
+	apperr.KindConflict:     {fiber.StatusConflict, response.ErrCodeDuplicate},
+	apperr.KindRateLimited:  {fiber.StatusTooManyRequests, response.ErrCodeRateLimited},
+	apperr.KindUnavailable:  {fiber.StatusServiceUnavailable, response.ErrCodeUnavailable},
+	apperr.KindInternal:     {fiber.StatusInternalServerError, response.ErrCodeInternal},
+}
+
+// statusCodes maps Fiber's own HTTP errors (routing, body limits) to error codes
+var statusCodes = map[int]string{
+	fiber.StatusBadRequest:            response.ErrCodeBadRequest,
+	fiber.StatusUnauthorized:          response.ErrCodeUnauthorized,
+	fiber.StatusForbidden:             response.ErrCodeForbidden,
+	fiber.StatusNotFound:              response.ErrCodeNotFound,
+	fiber.StatusConflict:              response.ErrCodeDuplicate,
+	fiber.StatusRequestEntityTooLarge: response.ErrCodeTooLarge,
+	fiber.StatusTooManyRequests:       response.ErrCodeRateLimited,
+	fiber.StatusServiceUnavailable:    response.ErrCodeUnavailable,
+	fiber.StatusInternalServerError:   response.ErrCodeInternal,
+}
+
+// ErrorHandler is the central Fiber error handler. It maps apperr errors,
+// validator errors and Fiber errors to the standard response envelope, and
+// logs anything that results in a 5xx. Validation messages, both field and
+// top-level, are localized according to the Accept-Language header.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	// Validation errors returned directly by a handler
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		if _, ok := apperr.As(err); !ok {
+			err = apperr.Validation("Invalid request data", err)
+		}
+	}
+
+	var (
+		status int
+		info   response.ErrorInfo
+	)
+
+	var fiberErr *fiber.Error
+	if appErr, ok := apperr.As(err); ok {
+		mapping, ok := kindMapping[appErr.Kind]
+		if !ok {
+			mapping = kindMapping[apperr.KindInternal]
+		}
+		status = mapping.status
+		info.Code = mapping.code
+		if appErr.Code != "" {
+			info.Code = appErr.Code
+		}
+		info.Message = appErr.Message
+
+		locale := c.AcceptsLanguages(validation.Locales...)
+		if appErr.Kind == apperr.KindValidation {
+			if msg, ok := validation.Message(appErr.Message, locale); ok {
+				info.Message = msg
+			}
+		}
+
+		fields := appErr.Fields
+		if verrs != nil {
+			fields = validation.Fields(verrs, locale)
+		} else {
+			fields = validation.LocalizeFields(fields, locale)
+		}
+		for _, fe := range fields {
+			info.Fields = append(info.Fields, response.FieldError{
+				Field:   fe.Field,
+				Rule:    fe.Rule,
+				Param:   fe.Param,
+				Message: fe.Message,
+			})
+		}
+	} else if errors.As(err, &fiberErr) {
+		status = fiberErr.Code
+		info.Code = statusCodes[status]
+		if info.Code == "" {
+			info.Code = response.ErrCodeBadRequest
+			if status >= fiber.StatusInternalServerError {
+				info.Code = response.ErrCodeInternal
+			}
+		}
+		info.Message = fiberErr.Message
+	} else {
+		status = fiber.StatusInternalServerError
+		info.Code = response.ErrCodeInternal
+		info.Message = "An internal error occurred"
+	}
+
+	if status >= fiber.StatusInternalServerError {
+		logger.FromFiber(c).Error("request failed",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Any("error", err),
+		)
+	}
+
+	return response.ErrorWithInfo(c, status, &info, nil)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+	"github.com/aliaxy/byte-cabinet/pkg/logger"
+	"github.com/aliaxy/byte-cabinet/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLogger creates middleware that attaches a request-scoped logger
+// carrying the request ID (and trace ID when traced) and writes one
+// structured access log per request.
+// It must be registered after the request ID and tracing middleware.
+func RequestLogger(base *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		reqLogger := base.With(slog.String(logger.RequestIDKey, requestid.FromFiber(c)))
+		if traceID := tracing.TraceID(c.UserContext()); traceID != "" {
+			reqLogger = reqLogger.With(slog.String("trace_id", traceID))
+		}
+		logger.WithFiber(c, reqLogger)
+
+		chainErr := c.Next()
+
+		// Let the error handler write the response so the logged status is final
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("ip", c.IP()),
+			slog.Int("bytes", len(c.Response().Body())),
+			slog.String("user_agent", c.Get(fiber.HeaderUserAgent)),
+		}
+		if chainErr != nil {
+			attrs = append(attrs, slog.String("error", chainErr.Error()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		reqLogger.LogAttrs(c.UserContext(), level, "request", attrs...)
+
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// spoofableSchemeHeaders are scheme headers Fiber's c.Protocol() honours but
+// that reverse proxies usually pass through unchanged from the client
+var spoofableSchemeHeaders = []string{
+	fiber.HeaderXForwardedProtocol,
+	fiber.HeaderXForwardedSsl,
+	fiber.HeaderXUrlScheme,
+}
+
+// ProxyHeaders creates middleware that resolves the client address from the
+// headers of a trusted proxy, so c.IP() and c.Protocol() reflect the
+// original client. It must be registered before anything that reads them.
+//
+// Proxies append to X-Forwarded-For and Forwarded, so everything left of the
+// entry added by the outermost trusted proxy is client-controlled. The list
+// is therefore walked from the right, skipping trusted proxies, and the first
+// other address is written back as the only X-Forwarded-For value. The
+// standard Forwarded header (RFC 7239) is translated into X-Forwarded-For and
+// X-Forwarded-Proto the same way. Scheme headers that no proxy sets are
+// discarded.
+func ProxyHeaders(cfg *config.ServerConfig) fiber.Handler {
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+
+	return func(c *fiber.Ctx) error {
+		header := &c.Request().Header
+		for _, h := range spoofableSchemeHeaders {
+			header.Del(h)
+		}
+		if !c.IsProxyTrusted() {
+			return c.Next()
+		}
+
+		switch cfg.ProxyHeader {
+		case config.ProxyHeaderXForwardedFor:
+			var chain []string
+			for _, ip := range strings.Split(c.Get(fiber.HeaderXForwardedFor), ",") {
+				chain = append(chain, strings.TrimSpace(ip))
+			}
+			header.Del(fiber.HeaderXForwardedFor)
+			if i := clientIndex(chain, trusted); i >= 0 {
+				header.Set(fiber.HeaderXForwardedFor, chain[i])
+			}
+
+		case config.ProxyHeaderForwarded:
+			chain, protos := parseForwarded(c.Get(fiber.HeaderForwarded))
+			header.Del(fiber.HeaderXForwardedFor)
+			header.Del(fiber.HeaderXForwardedProto)
+			if i := clientIndex(chain, trusted); i >= 0 {
+				header.Set(fiber.HeaderXForwardedFor, chain[i])
+				if proto := protos[i]; proto == "http" || proto == "https" {
+					header.Set(fiber.HeaderXForwardedProto, proto)
+				}
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// clientIndex returns the index of the rightmost address in chain that is
+// not a trusted proxy, or -1 if there is none. An unparseable address
+// before the client is reached ends the search, since nothing left of it
+// can be attributed to a trusted proxy.
+func clientIndex(chain []string, trusted []*net.IPNet) int {
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := net.ParseIP(chain[i])
+		if ip == nil {
+			return -1
+		}
+		if !containsIP(trusted, ip) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseForwarded splits a Forwarded header into the "for" address and
+// "proto" of each element. Obfuscated and unknown nodes yield an empty
+// address.
+func parseForwarded(forwarded string) (ips, protos []string) {
+	if forwarded == "" {
+		return nil, nil
+	}
+	for _, element := range strings.Split(forwarded, ",") {
+		var ip, proto string
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"`)
+
+			switch strings.ToLower(key) {
+			case "for":
+				ip = forwardedNodeIP(value)
+			case "proto":
+				proto = strings.ToLower(value)
+			}
+		}
+		ips = append(ips, ip)
+		protos = append(protos, proto)
+	}
+	return ips, protos
+}
+
+// forwardedNodeIP extracts the IP from a Forwarded "for" node such as
+// 192.0.2.60, "192.0.2.60:4711" or "[2001:db8::1]:4711". Obfuscated and
+// unknown nodes yield an empty string.
+func forwardedNodeIP(node string) string {
+	if strings.HasPrefix(node, "[") {
+		end := strings.IndexByte(node, ']')
+		if end < 0 {
+			return ""
+		}
+		node = node[1:end]
+	} else if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+
+	if net.ParseIP(node) == nil {
+		return ""
+	}
+	return node
+}
+
+// parseTrustedProxies converts server.trusted_proxies into networks; single
+// addresses become /32 or /128 networks. Entries are validated with the
+// configuration, so invalid ones are skipped.
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				continue
+			}
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, net.IPv4len*8
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if _, n, err := net.ParseCIDR(proxy); err == nil {
+			nets = append(nets, n)
+		}
+	}
+	return nets
+}
+
+// containsIP reports whether ip is in one of nets
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Reloadable wraps a middleware that can be replaced at runtime, e.g. when
+// the configuration it was built from is reloaded
+type Reloadable struct {
+	current atomic.Pointer[fiber.Handler]
+}
+
+// NewReloadable creates a reloadable middleware initially running h
+func NewReloadable(h fiber.Handler) *Reloadable {
+	r := &Reloadable{}
+	r.Swap(h)
+	return r
+}
+
+// Swap replaces the wrapped middleware; in-flight requests finish with the
+// previous one
+func (r *Reloadable) Swap(h fiber.Handler) {
+	r.current.Store(&h)
+}
+
+// Handler returns the middleware to register with Fiber
+func (r *Reloadable) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*r.current.Load())(c)
+	}
+}
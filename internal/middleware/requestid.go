@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/aliaxy/byte-cabinet/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID creates middleware that assigns every request an ID.
+// A valid X-Request-ID sent by the client (or a proxy in front of the
+// server) is reused, otherwise a new one is generated. The ID is stored in
+// the fiber locals and the request context, and echoed in the response.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		requestid.WithFiber(c, id)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), id))
+		c.Set(requestid.Header, id)
+
+		return c.Next()
+	}
+}
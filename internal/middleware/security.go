@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SecurityHeaders creates middleware that sets HTTP security headers.
+// HSTS is only sent when production is true, since it would pin browsers
+// to HTTPS for local development hosts.
+func SecurityHeaders(cfg config.SecurityConfig, production bool) fiber.Handler {
+	csp := NewCSPBuilder()
+	for directive, sources := range cfg.CSP.Directives {
+		csp.Add(directive, sources...)
+	}
+	if cfg.CSP.ReportURI != "" {
+		csp.Add("report-uri", cfg.CSP.ReportURI)
+	}
+
+	cspHeader := fiber.HeaderContentSecurityPolicy
+	if cfg.CSP.ReportOnly {
+		cspHeader = fiber.HeaderContentSecurityPolicyReportOnly
+	}
+
+	// The policy only changes per request when nonces are enabled
+	staticPolicy := csp.Build("")
+
+	hsts := ""
+	if production && cfg.HSTS.MaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTS.MaxAge)
+		if cfg.HSTS.IncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTS.Preload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.ContentTypeNosniff {
+			c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		}
+		if cfg.FrameOptions != "" {
+			c.Set(fiber.HeaderXFrameOptions, cfg.FrameOptions)
+		}
+		if cfg.XSSProtection != "" {
+			c.Set(fiber.HeaderXXSSProtection, cfg.XSSProtection)
+		}
+		if cfg.ReferrerPolicy != "" {
+			c.Set(fiber.HeaderReferrerPolicy, cfg.ReferrerPolicy)
+		}
+		if cfg.PermissionsPolicy != "" {
+			c.Set(fiber.HeaderPermissionsPolicy, cfg.PermissionsPolicy)
+		}
+		if hsts != "" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+
+		if cfg.CSP.Enabled {
+			policy := staticPolicy
+			if cfg.CSP.UseNonce {
+				nonce, err := generateNonce()
+				if err != nil {
+					return err
+				}
+				c.Locals("cspNonce", nonce)
+				policy = csp.Build(nonce)
+			}
+			c.Set(cspHeader, policy)
+		}
+
+		return c.Next()
+	}
+}
+
+// GetCSPNonce retrieves the per-request CSP nonce for inline scripts
+func GetCSPNonce(c *fiber.Ctx) (string, bool) {
+	nonce, ok := c.Locals("cspNonce").(string)
+	return nonce, ok
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// spaIndex is the entry point served for client-side routes
+const spaIndex = "index.html"
+
+// SPA creates middleware serving a single-page application from fsys.
+// Existing files are served directly; other GET requests without a file
+// extension fall back to index.html so the client-side router can handle
+// them. Requests for the given API prefixes, or any path below them, are
+// passed on unchanged; "/api" matches "/api" and "/api/posts" but not
+// "/apiary".
+func SPA(fsys fs.FS, apiPrefixes ...string) fiber.Handler {
+	root := http.FS(fsys)
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+		for _, prefix := range apiPrefixes {
+			if underPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		name := strings.TrimPrefix(path.Clean(c.Path()), "/")
+		if name != "" {
+			if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+				// Vite fingerprints everything under assets/
+				if strings.HasPrefix(name, "assets/") {
+					c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+				}
+				return filesystem.SendFile(c, root, name)
+			} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			// A missing asset is a real 404, not a client-side route
+			if path.Ext(name) != "" {
+				return c.Next()
+			}
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		return filesystem.SendFile(c, root, spaIndex)
+	}
+}
+
+// underPrefix reports whether p is prefix or a path below it
+func underPrefix(p, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return p == prefix || strings.HasPrefix(p, prefix+"/")
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing creates middleware that starts a server span per request.
+// Incoming W3C trace-context headers are honoured, and the span is stored in
+// the request's user context so handlers pass it on via c.UserContext().
+// It must run outside any middleware that converts errors into responses
+// so the final status code is recorded.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.UserContext(), headerCarrier{c})
+
+		ctx, span := tracing.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.URLScheme(c.Protocol()),
+				semconv.ClientAddress(c.IP()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+			span.RecordError(err)
+		}
+
+		// Name the span after the route template to keep cardinality low
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+
+		return err
+	}
+}
+
+// headerCarrier adapts fiber request and response headers to the
+// propagation.TextMapCarrier interface
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get returns the request header value for key
+func (hc headerCarrier) Get(key string) string {
+	return hc.c.Get(key)
+}
+
+// Set sets a response header
+func (hc headerCarrier) Set(key, value string) {
+	hc.c.Set(key, value)
+}
+
+// Keys lists the request header names
+func (hc headerCarrier) Keys() []string {
+	keys := make([]string, 0)
+	for key := range hc.c.GetReqHeaders() {
+		keys = append(keys, key)
+	}
+	return keys
+}
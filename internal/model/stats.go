@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// StatusCount represents the number of rows sharing a status value
+type StatusCount struct {
+	Status string `db:"status" json:"status"`
+	Count  int64  `db:"count" json:"count"`
+}
+
+// Device classes recorded by the view analytics
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+)
+
+// ViewRequest is the optional body of a post view beacon
+type ViewRequest struct {
+	// Referrer is document.referrer of the page showing the post. Only its
+	// domain is kept.
+	Referrer string `json:"referrer" validate:"omitempty,max=2048"`
+}
+
+// DailyViews is the number of views on one UTC day
+type DailyViews struct {
+	Day   time.Time `db:"day" json:"-"`
+	Date  string    `db:"-" json:"date"` // YYYY-MM-DD
+	Views int64     `db:"views" json:"views"`
+}
+
+// ReferrerViews is the number of views from one referring domain. An
+// empty referrer stands for direct and internal visits.
+type ReferrerViews struct {
+	Referrer string `db:"referrer" json:"referrer"`
+	Views    int64  `db:"views" json:"views"`
+}
+
+// DeviceViews is the number of views from one device class
+type DeviceViews struct {
+	Device string `db:"device" json:"device"`
+	Views  int64  `db:"views" json:"views"`
+}
+
+// PostViews is the number of views of a post within a date range
+type PostViews struct {
+	ID         int64  `db:"id" json:"id"`
+	Title      string `db:"title" json:"title"`
+	Slug       string `db:"slug" json:"slug"`
+	Views      int64  `db:"views" json:"views"`
+	TotalViews int64  `db:"total_views" json:"total_views"` // all-time view count
+}
+
+// StatsOverview is the dashboard summary for a date range
+type StatsOverview struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	TotalViews   int64           `json:"total_views"`
+	Series       []DailyViews    `json:"series"`
+	Devices      []DeviceViews   `json:"devices"`
+	TopReferrers []ReferrerViews `json:"top_referrers"`
+	TopPosts     []PostViews     `json:"top_posts"`
+	Posts        []StatusCount   `json:"posts"`    // posts by status
+	Comments     []StatusCount   `json:"comments"` // comments by status
+}
+
+// PostStatsList ranks posts by views within a date range
+type PostStatsList struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Posts []PostViews `json:"posts"`
+}
+
+// PostStats is the analytics of a single post within a date range
+type PostStats struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Post      PostViews       `json:"post"`
+	Series    []DailyViews    `json:"series"`
+	Devices   []DeviceViews   `json:"devices"`
+	Referrers []ReferrerViews `json:"referrers"`
+}
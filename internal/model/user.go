@@ -23,6 +23,14 @@ type UserLogin struct {
 	Password string `json:"password" validate:"required,min=6"`
 }
 
+// UserCreate represents the data needed to create a user
+type UserCreate struct {
+	Username    string `json:"username" validate:"required,min=3,max=50,alphanum"`
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required,max=72"` // Further rules are enforced by the password policy
+	DisplayName string `json:"display_name" validate:"omitempty,max=100"`
+}
+
 // UserUpdate represents update profile request payload
 type UserUpdate struct {
 	DisplayName string `json:"display_name" validate:"omitempty,max=100"`
@@ -34,7 +42,7 @@ type UserUpdate struct {
 // PasswordChange represents password change request payload
 type PasswordChange struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=6,max=72"`
+	NewPassword string `json:"new_password" validate:"required,max=72"` // Further rules are enforced by the password policy
 }
 
 // UserResponse represents user data in API responses
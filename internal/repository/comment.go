@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CommentRepository handles comment data access
+type CommentRepository struct {
+	reader sqlx.ExtContext
+	writer sqlx.ExtContext
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *database.DB) *CommentRepository {
+	return &CommentRepository{reader: db.Reader(), writer: db.Writer()}
+}
+
+// WithTx returns a copy of the repository that runs all statements,
+// including reads, in the given transaction
+func (r *CommentRepository) WithTx(tx sqlx.ExtContext) *CommentRepository {
+	return &CommentRepository{reader: tx, writer: tx}
+}
+
+// DeleteSpamBefore deletes comments marked as spam that were created before
+// the given time and returns how many were removed
+func (r *CommentRepository) DeleteSpamBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepository.DeleteSpamBefore")
+	defer span.End()
+
+	query := `DELETE FROM comments WHERE status = 'spam' AND created_at < ?`
+
+	result, err := r.writer.ExecContext(ctx, r.writer.Rebind(query), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
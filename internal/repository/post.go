@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostRepository handles post data access
+type PostRepository struct {
+	reader sqlx.ExtContext
+	writer sqlx.ExtContext
+}
+
+// NewPostRepository creates a new post repository
+func NewPostRepository(db *database.DB) *PostRepository {
+	return &PostRepository{reader: db.Reader(), writer: db.Writer()}
+}
+
+// WithTx returns a copy of the repository that runs all statements,
+// including reads, in the given transaction
+func (r *PostRepository) WithTx(tx sqlx.ExtContext) *PostRepository {
+	return &PostRepository{reader: tx, writer: tx}
+}
+
+// IsPublished reports whether a published post with the given ID exists
+func (r *PostRepository) IsPublished(ctx context.Context, id int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PostRepository.IsPublished")
+	defer span.End()
+
+	var one int
+	query := `SELECT 1 FROM posts WHERE id = ? AND status = ?`
+
+	err := sqlx.GetContext(ctx, r.reader, &one, r.reader.Rebind(query), id, model.PostStatusPublished)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// AddViews increases the view count of a post. It reports false if the
+// post no longer exists.
+func (r *PostRepository) AddViews(ctx context.Context, id, views int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "PostRepository.AddViews")
+	defer span.End()
+
+	query := `UPDATE posts SET view_count = view_count + ? WHERE id = ?`
+
+	result, err := r.writer.ExecContext(ctx, r.writer.Rebind(query), views, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// AddDailyViews increases the views of a post in the daily analytics for
+// one day, referrer and device class. The post must exist.
+func (r *PostRepository) AddDailyViews(ctx context.Context, day string, postID int64, referrer, device string, views int64) error {
+	ctx, span := tracing.Start(ctx, "PostRepository.AddDailyViews")
+	defer span.End()
+
+	query := `
+		INSERT INTO page_views_daily (day, post_id, referrer, device, views)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (day, post_id, referrer, device)
+		DO UPDATE SET views = page_views_daily.views + excluded.views
+	`
+
+	_, err := r.writer.ExecContext(ctx, r.writer.Rebind(query), day, postID, referrer, device, views)
+	return err
+}
@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/database/dbtest"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestPostRepository(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := repository.NewPostRepository(db)
+
+		author := createUser(t, db, "author")
+		published := createPost(t, db, author, "published", model.PostStatusPublished)
+		draft := createPost(t, db, author, "draft", model.PostStatusDraft)
+
+		for _, tt := range []struct {
+			id   int64
+			want bool
+		}{{published, true}, {draft, false}, {published + 1000, false}} {
+			if got, err := repo.IsPublished(ctx, tt.id); err != nil || got != tt.want {
+				t.Errorf("IsPublished(%d) = %v, %v; want %v", tt.id, got, err, tt.want)
+			}
+		}
+
+		// Views are written in a transaction, as the view tracker does
+		err := db.WithTx(ctx, func(tx *sqlx.Tx) error {
+			posts := repo.WithTx(tx)
+			if exists, err := posts.AddViews(ctx, published, 3); err != nil || !exists {
+				t.Errorf("AddViews = %v, %v", exists, err)
+			}
+			if exists, err := posts.AddViews(ctx, published+1000, 1); err != nil || exists {
+				t.Errorf("AddViews of a missing post = %v, %v", exists, err)
+			}
+			for _, views := range []int64{2, 5} {
+				if err := posts.AddDailyViews(ctx, "2026-03-01", published, "example.com", model.DeviceMobile, views); err != nil {
+					return err
+				}
+			}
+			return posts.AddDailyViews(ctx, "2026-03-01", published, "", model.DeviceDesktop, 1)
+		})
+		if err != nil {
+			t.Fatalf("write views: %v", err)
+		}
+
+		var viewCount int64
+		if err := db.Get(&viewCount, db.Rebind(`SELECT view_count FROM posts WHERE id = ?`), published); err != nil {
+			t.Fatal(err)
+		}
+		if viewCount != 3 {
+			t.Errorf("view_count = %d, want 3", viewCount)
+		}
+
+		// Repeated daily views for the same key are summed
+		var views int64
+		query := db.Rebind(`
+			SELECT views FROM page_views_daily
+			WHERE day = ? AND post_id = ? AND referrer = ? AND device = ?
+		`)
+		if err := db.Get(&views, query, "2026-03-01", published, "example.com", model.DeviceMobile); err != nil {
+			t.Fatal(err)
+		}
+		if views != 7 {
+			t.Errorf("daily views = %d, want 7", views)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+)
+
+// createUser inserts a user through the repository and returns its ID
+func createUser(t *testing.T, db *database.DB, username string) int64 {
+	t.Helper()
+	user := &model.User{
+		Username:     username,
+		Email:        username + "@example.com",
+		PasswordHash: "hash",
+		DisplayName:  username,
+	}
+	if err := repository.NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user.ID
+}
+
+// createPost inserts a post and returns its ID
+func createPost(t *testing.T, db *database.DB, authorID int64, slug string, status model.PostStatus) int64 {
+	t.Helper()
+	var id int64
+	query := db.Rebind(`
+		INSERT INTO posts (title, slug, content, author_id, status)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`)
+	if err := db.Get(&id, query, "Title of "+slug, slug, "content", authorID, status); err != nil {
+		t.Fatalf("create post %s: %v", slug, err)
+	}
+	return id
+}
+
+// createComment inserts a comment with the given status
+func createComment(t *testing.T, db *database.DB, postID int64, status string) {
+	t.Helper()
+	query := db.Rebind(`
+		INSERT INTO comments (post_id, author_name, author_email, content, status)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if _, err := db.Exec(query, postID, "reader", "reader@example.com", "comment", status); err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// StatsRepository handles aggregate queries used for statistics. All of
+// them are read-only and use the read pool.
+type StatsRepository struct {
+	reader sqlx.ExtContext
+}
+
+// NewStatsRepository creates a new stats repository
+func NewStatsRepository(db *database.DB) *StatsRepository {
+	return &StatsRepository{reader: db.Reader()}
+}
+
+// CountPostsByStatus returns the number of posts for each status
+func (r *StatsRepository) CountPostsByStatus(ctx context.Context) ([]model.StatusCount, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.CountPostsByStatus")
+	defer span.End()
+
+	var counts []model.StatusCount
+	query := `SELECT status, COUNT(*) AS count FROM posts GROUP BY status`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &counts, query); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountCommentsByStatus returns the number of comments for each status
+func (r *StatsRepository) CountCommentsByStatus(ctx context.Context) ([]model.StatusCount, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.CountCommentsByStatus")
+	defer span.End()
+
+	var counts []model.StatusCount
+	query := `SELECT status, COUNT(*) AS count FROM comments GROUP BY status`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &counts, query); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// The analytics queries below cover the UTC days from and to inclusive,
+// given as YYYY-MM-DD. A postID of 0 includes all posts.
+
+// DailyViews returns the views per day, omitting days without views
+func (r *StatsRepository) DailyViews(ctx context.Context, from, to string, postID int64) ([]model.DailyViews, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.DailyViews")
+	defer span.End()
+
+	var series []model.DailyViews
+	query := `
+		SELECT day, SUM(views) AS views
+		FROM page_views_daily
+		WHERE day BETWEEN ? AND ? AND (? = 0 OR post_id = ?)
+		GROUP BY day
+		ORDER BY day
+	`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &series, r.reader.Rebind(query), from, to, postID, postID); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// DeviceViews returns the views per device class
+func (r *StatsRepository) DeviceViews(ctx context.Context, from, to string, postID int64) ([]model.DeviceViews, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.DeviceViews")
+	defer span.End()
+
+	var devices []model.DeviceViews
+	query := `
+		SELECT device, SUM(views) AS views
+		FROM page_views_daily
+		WHERE day BETWEEN ? AND ? AND (? = 0 OR post_id = ?)
+		GROUP BY device
+		ORDER BY views DESC, device
+	`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &devices, r.reader.Rebind(query), from, to, postID, postID); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// TopReferrers returns the referring domains with the most views
+func (r *StatsRepository) TopReferrers(ctx context.Context, from, to string, postID int64, limit int) ([]model.ReferrerViews, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.TopReferrers")
+	defer span.End()
+
+	var referrers []model.ReferrerViews
+	query := `
+		SELECT referrer, SUM(views) AS views
+		FROM page_views_daily
+		WHERE day BETWEEN ? AND ? AND (? = 0 OR post_id = ?)
+		GROUP BY referrer
+		ORDER BY views DESC, referrer
+		LIMIT ?
+	`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &referrers, r.reader.Rebind(query), from, to, postID, postID, limit); err != nil {
+		return nil, err
+	}
+	return referrers, nil
+}
+
+// TopPosts returns the posts with the most views
+func (r *StatsRepository) TopPosts(ctx context.Context, from, to string, limit int) ([]model.PostViews, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.TopPosts")
+	defer span.End()
+
+	var posts []model.PostViews
+	query := `
+		SELECT p.id, p.title, p.slug, SUM(v.views) AS views, p.view_count AS total_views
+		FROM page_views_daily v
+		JOIN posts p ON p.id = v.post_id
+		WHERE v.day BETWEEN ? AND ?
+		GROUP BY p.id, p.title, p.slug, p.view_count
+		ORDER BY views DESC, p.id
+		LIMIT ?
+	`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &posts, r.reader.Rebind(query), from, to, limit); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// PostViews returns the views of a single post
+func (r *StatsRepository) PostViews(ctx context.Context, from, to string, postID int64) (*model.PostViews, error) {
+	ctx, span := tracing.Start(ctx, "StatsRepository.PostViews")
+	defer span.End()
+
+	var post model.PostViews
+	query := `
+		SELECT p.id, p.title, p.slug, p.view_count AS total_views,
+		       COALESCE((
+		           SELECT SUM(v.views) FROM page_views_daily v
+		           WHERE v.post_id = p.id AND v.day BETWEEN ? AND ?
+		       ), 0) AS views
+		FROM posts p
+		WHERE p.id = ?
+	`
+
+	err := sqlx.GetContext(ctx, r.reader, &post, r.reader.Rebind(query), from, to, postID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &post, nil
+}
@@ -0,0 +1,145 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/database/dbtest"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+)
+
+func TestStatsRepository(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		posts := repository.NewPostRepository(db)
+		stats := repository.NewStatsRepository(db)
+
+		author := createUser(t, db, "author")
+		first := createPost(t, db, author, "first", model.PostStatusPublished)
+		second := createPost(t, db, author, "second", model.PostStatusPublished)
+		createPost(t, db, author, "draft", model.PostStatusDraft)
+		createComment(t, db, first, "approved")
+		createComment(t, db, first, "approved")
+		createComment(t, db, second, "spam")
+
+		for _, v := range []struct {
+			day      string
+			post     int64
+			referrer string
+			device   string
+			views    int64
+		}{
+			{"2026-03-01", first, "news.example.com", model.DeviceDesktop, 4},
+			{"2026-03-01", first, "", model.DeviceMobile, 1},
+			{"2026-03-02", second, "news.example.com", model.DeviceMobile, 2},
+			{"2026-03-03", first, "search.example.com", model.DeviceTablet, 3},
+			{"2026-02-28", second, "old.example.com", model.DeviceDesktop, 50}, // outside the range
+		} {
+			if err := posts.AddDailyViews(ctx, v.day, v.post, v.referrer, v.device, v.views); err != nil {
+				t.Fatalf("AddDailyViews: %v", err)
+			}
+		}
+		if _, err := posts.AddViews(ctx, first, 8); err != nil {
+			t.Fatal(err)
+		}
+
+		const from, to = "2026-03-01", "2026-03-03"
+
+		daily, err := stats.DailyViews(ctx, from, to, 0)
+		if err != nil {
+			t.Fatalf("DailyViews: %v", err)
+		}
+		var days []string
+		var views []int64
+		for _, d := range daily {
+			days = append(days, d.Day.UTC().Format(time.DateOnly))
+			views = append(views, d.Views)
+		}
+		if want := []string{"2026-03-01", "2026-03-02", "2026-03-03"}; !reflect.DeepEqual(days, want) {
+			t.Errorf("DailyViews days = %v, want %v", days, want)
+		}
+		if want := []int64{5, 2, 3}; !reflect.DeepEqual(views, want) {
+			t.Errorf("DailyViews views = %v, want %v", views, want)
+		}
+
+		daily, err = stats.DailyViews(ctx, from, to, second)
+		if err != nil || len(daily) != 1 || daily[0].Views != 2 {
+			t.Errorf("DailyViews of one post = %+v, %v", daily, err)
+		}
+
+		devices, err := stats.DeviceViews(ctx, from, to, 0)
+		if err != nil {
+			t.Fatalf("DeviceViews: %v", err)
+		}
+		wantDevices := []model.DeviceViews{
+			{Device: model.DeviceDesktop, Views: 4},
+			{Device: model.DeviceMobile, Views: 3},
+			{Device: model.DeviceTablet, Views: 3},
+		}
+		if !reflect.DeepEqual(devices, wantDevices) {
+			t.Errorf("DeviceViews = %+v, want %+v", devices, wantDevices)
+		}
+
+		referrers, err := stats.TopReferrers(ctx, from, to, 0, 2)
+		if err != nil {
+			t.Fatalf("TopReferrers: %v", err)
+		}
+		wantReferrers := []model.ReferrerViews{
+			{Referrer: "news.example.com", Views: 6},
+			{Referrer: "search.example.com", Views: 3},
+		}
+		if !reflect.DeepEqual(referrers, wantReferrers) {
+			t.Errorf("TopReferrers = %+v, want %+v", referrers, wantReferrers)
+		}
+
+		top, err := stats.TopPosts(ctx, from, to, 10)
+		if err != nil {
+			t.Fatalf("TopPosts: %v", err)
+		}
+		if len(top) != 2 || top[0].ID != first || top[0].Views != 8 || top[0].TotalViews != 8 ||
+			top[1].ID != second || top[1].Views != 2 {
+			t.Errorf("TopPosts = %+v", top)
+		}
+
+		post, err := stats.PostViews(ctx, from, to, second)
+		if err != nil {
+			t.Fatalf("PostViews: %v", err)
+		}
+		if post.ID != second || post.Slug != "second" || post.Views != 2 {
+			t.Errorf("PostViews = %+v", post)
+		}
+		if _, err := stats.PostViews(ctx, from, to, second+1000); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("PostViews of a missing post: %v, want ErrNotFound", err)
+		}
+
+		postCounts, err := stats.CountPostsByStatus(ctx)
+		if err != nil {
+			t.Fatalf("CountPostsByStatus: %v", err)
+		}
+		if got, want := countMap(postCounts), map[string]int64{"published": 2, "draft": 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CountPostsByStatus = %v, want %v", got, want)
+		}
+
+		commentCounts, err := stats.CountCommentsByStatus(ctx)
+		if err != nil {
+			t.Fatalf("CountCommentsByStatus: %v", err)
+		}
+		if got, want := countMap(commentCounts), map[string]int64{"approved": 2, "spam": 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CountCommentsByStatus = %v, want %v", got, want)
+		}
+	})
+}
+
+// countMap converts status counts, which have no defined order, to a map
+func countMap(counts []model.StatusCount) map[string]int64 {
+	m := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		m[c.Status] = c.Count
+	}
+	return m
+}
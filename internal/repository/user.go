@@ -6,7 +6,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aliaxy/byte-cabinet/internal/database"
 	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -17,18 +19,29 @@ var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 )
 
-// UserRepository handles user data access
+// UserRepository handles user data access. Queries go to the read pool
+// and statements that modify data to the writer.
 type UserRepository struct {
-	db *sqlx.DB
+	reader sqlx.ExtContext
+	writer sqlx.ExtContext
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *sqlx.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{reader: db.Reader(), writer: db.Writer()}
+}
+
+// WithTx returns a copy of the repository that runs all statements,
+// including reads, in the given transaction
+func (r *UserRepository) WithTx(tx sqlx.ExtContext) *UserRepository {
+	return &UserRepository{reader: tx, writer: tx}
 }
 
 // GetByID retrieves a user by their ID
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetByID")
+	defer span.End()
+
 	var user model.User
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar, bio, created_at, updated_at
@@ -36,7 +49,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, er
 		WHERE id = ?
 	`
 
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := sqlx.GetContext(ctx, r.reader, &user, r.reader.Rebind(query), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -49,6 +62,9 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*model.User, er
 
 // GetByUsername retrieves a user by their username
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetByUsername")
+	defer span.End()
+
 	var user model.User
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar, bio, created_at, updated_at
@@ -56,7 +72,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 		WHERE username = ?
 	`
 
-	err := r.db.GetContext(ctx, &user, query, username)
+	err := sqlx.GetContext(ctx, r.reader, &user, r.reader.Rebind(query), username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -67,8 +83,30 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	return &user, nil
 }
 
+// List retrieves all users ordered by ID
+func (r *UserRepository) List(ctx context.Context) ([]model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.List")
+	defer span.End()
+
+	var users []model.User
+	query := `
+		SELECT id, username, email, password_hash, display_name, avatar, bio, created_at, updated_at
+		FROM users
+		ORDER BY id
+	`
+
+	if err := sqlx.SelectContext(ctx, r.reader, &users, query); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 // GetByEmail retrieves a user by their email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.GetByEmail")
+	defer span.End()
+
 	var user model.User
 	query := `
 		SELECT id, username, email, password_hash, display_name, avatar, bio, created_at, updated_at
@@ -76,7 +114,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 		WHERE email = ?
 	`
 
-	err := r.db.GetContext(ctx, &user, query, email)
+	err := sqlx.GetContext(ctx, r.reader, &user, r.reader.Rebind(query), email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -87,18 +125,24 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	return &user, nil
 }
 
-// Create creates a new user
+// Create creates a new user. It returns ErrUserAlreadyExists if the
+// username or email is taken.
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.Create")
+	defer span.End()
+
 	query := `
 		INSERT INTO users (username, email, password_hash, display_name, avatar, bio, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`
 
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
-	result, err := r.db.ExecContext(ctx, query,
+	// RETURNING works on both SQLite and PostgreSQL, which has no LastInsertId
+	err := r.writer.QueryRowxContext(ctx, r.writer.Rebind(query),
 		user.Username,
 		user.Email,
 		user.PasswordHash,
@@ -107,22 +151,18 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 		user.Bio,
 		user.CreatedAt,
 		user.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
+	).Scan(&user.ID)
+	if database.IsUniqueViolation(err) {
+		return ErrUserAlreadyExists
 	}
-
-	user.ID = id
-	return nil
+	return err
 }
 
 // Update updates an existing user
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.Update")
+	defer span.End()
+
 	query := `
 		UPDATE users
 		SET email = ?, display_name = ?, avatar = ?, bio = ?, updated_at = ?
@@ -131,7 +171,7 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 
 	user.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := r.writer.ExecContext(ctx, r.writer.Rebind(query),
 		user.Email,
 		user.DisplayName,
 		user.Avatar,
@@ -157,13 +197,16 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 
 // UpdatePassword updates a user's password
 func (r *UserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.UpdatePassword")
+	defer span.End()
+
 	query := `
 		UPDATE users
 		SET password_hash = ?, updated_at = ?
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query, passwordHash, time.Now(), id)
+	result, err := r.writer.ExecContext(ctx, r.writer.Rebind(query), passwordHash, time.Now(), id)
 	if err != nil {
 		return err
 	}
@@ -182,10 +225,13 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id int64, passwordH
 
 // ExistsByUsername checks if a user with the given username exists
 func (r *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.ExistsByUsername")
+	defer span.End()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE username = ?`
 
-	err := r.db.GetContext(ctx, &count, query, username)
+	err := sqlx.GetContext(ctx, r.reader, &count, r.reader.Rebind(query), username)
 	if err != nil {
 		return false, err
 	}
@@ -195,10 +241,13 @@ func (r *UserRepository) ExistsByUsername(ctx context.Context, username string)
 
 // ExistsByEmail checks if a user with the given email exists
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "UserRepository.ExistsByEmail")
+	defer span.End()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE email = ?`
 
-	err := r.db.GetContext(ctx, &count, query, email)
+	err := sqlx.GetContext(ctx, r.reader, &count, r.reader.Rebind(query), email)
 	if err != nil {
 		return false, err
 	}
@@ -208,6 +257,9 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 
 // UpdateProfile updates user profile fields
 func (r *UserRepository) UpdateProfile(ctx context.Context, id int64, req *model.UserUpdate) error {
+	ctx, span := tracing.Start(ctx, "UserRepository.UpdateProfile")
+	defer span.End()
+
 	query := `
 		UPDATE users
 		SET display_name = COALESCE(NULLIF(?, ''), display_name),
@@ -218,7 +270,7 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, id int64, req *model
 		WHERE id = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := r.writer.ExecContext(ctx, r.writer.Rebind(query),
 		req.DisplayName,
 		req.Email,
 		req.Avatar,
@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/database/dbtest"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+)
+
+func TestUserRepository(t *testing.T) {
+	dbtest.Run(t, func(t *testing.T, db *database.DB) {
+		ctx := context.Background()
+		repo := repository.NewUserRepository(db)
+
+		id := createUser(t, db, "alice")
+		if id == 0 {
+			t.Fatal("Create did not set the ID")
+		}
+
+		user, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if user.Username != "alice" || user.Email != "alice@example.com" || user.PasswordHash != "hash" {
+			t.Errorf("GetByID = %+v", user)
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Errorf("timestamps not set: %+v", user)
+		}
+
+		if got, err := repo.GetByUsername(ctx, "alice"); err != nil || got.ID != id {
+			t.Errorf("GetByUsername = %v, %v", got, err)
+		}
+		if got, err := repo.GetByEmail(ctx, "alice@example.com"); err != nil || got.ID != id {
+			t.Errorf("GetByEmail = %v, %v", got, err)
+		}
+		if _, err := repo.GetByID(ctx, id+1000); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("GetByID of a missing user: %v, want ErrNotFound", err)
+		}
+		if _, err := repo.GetByUsername(ctx, "nobody"); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("GetByUsername of a missing user: %v, want ErrNotFound", err)
+		}
+
+		if exists, err := repo.ExistsByUsername(ctx, "alice"); err != nil || !exists {
+			t.Errorf("ExistsByUsername(alice) = %v, %v", exists, err)
+		}
+		if exists, err := repo.ExistsByEmail(ctx, "nobody@example.com"); err != nil || exists {
+			t.Errorf("ExistsByEmail(nobody) = %v, %v", exists, err)
+		}
+
+		// Usernames and emails are unique
+		duplicate := &model.User{Username: "alice", Email: "other@example.com", PasswordHash: "hash"}
+		if err := repo.Create(ctx, duplicate); !errors.Is(err, repository.ErrUserAlreadyExists) {
+			t.Errorf("Create(duplicate username) = %v, want ErrUserAlreadyExists", err)
+		}
+		duplicate = &model.User{Username: "other", Email: user.Email, PasswordHash: "hash"}
+		if err := repo.Create(ctx, duplicate); !errors.Is(err, repository.ErrUserAlreadyExists) {
+			t.Errorf("Create(duplicate email) = %v, want ErrUserAlreadyExists", err)
+		}
+
+		user.DisplayName = "Alice"
+		user.Bio = "Writes things"
+		if err := repo.Update(ctx, user); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if err := repo.UpdatePassword(ctx, id, "new-hash"); err != nil {
+			t.Fatalf("UpdatePassword: %v", err)
+		}
+		// Empty fields keep their value
+		if err := repo.UpdateProfile(ctx, id, &model.UserUpdate{Email: "alice@example.org"}); err != nil {
+			t.Fatalf("UpdateProfile: %v", err)
+		}
+
+		user, err = repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.DisplayName != "Alice" || user.Bio != "Writes things" ||
+			user.Email != "alice@example.org" || user.PasswordHash != "new-hash" {
+			t.Errorf("after updates = %+v", user)
+		}
+
+		if err := repo.Update(ctx, &model.User{ID: id + 1000}); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("Update of a missing user: %v, want ErrUserNotFound", err)
+		}
+		if err := repo.UpdatePassword(ctx, id+1000, "x"); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("UpdatePassword of a missing user: %v, want ErrUserNotFound", err)
+		}
+		if err := repo.UpdateProfile(ctx, id+1000, &model.UserUpdate{Bio: "x"}); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("UpdateProfile of a missing user: %v, want ErrNotFound", err)
+		}
+
+		createUser(t, db, "bob")
+		users, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		found := map[string]bool{}
+		for _, u := range users {
+			found[u.Username] = true
+		}
+		if !found["alice"] || !found["bob"] {
+			t.Errorf("List = %v, want alice and bob", users)
+		}
+	})
+}
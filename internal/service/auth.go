@@ -3,29 +3,43 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
 
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
 	"github.com/aliaxy/byte-cabinet/internal/model"
 	"github.com/aliaxy/byte-cabinet/internal/repository"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
 	"github.com/aliaxy/byte-cabinet/pkg/utils"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid username or password")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidOldPassword = errors.New("invalid old password")
+	ErrInvalidCredentials   = apperr.New(apperr.KindUnauthorized, "Invalid username or password")
+	ErrInvalidRefreshToken  = apperr.New(apperr.KindUnauthorized, "Invalid or expired refresh token")
+	ErrUserNotFound         = apperr.New(apperr.KindNotFound, "User not found")
+	ErrInvalidOldPassword   = apperr.New(apperr.KindBadRequest, "Current password is incorrect")
+	ErrPasswordPolicyFailed = apperr.New(apperr.KindValidation, "Password does not meet the password policy")
 )
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo   *repository.UserRepository
-	jwtManager *utils.JWTManager
+	userRepo       *repository.UserRepository
+	jwtManager     *utils.JWTManager
+	passwordPolicy *utils.PasswordPolicy
+	bcryptCost     int
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userRepo *repository.UserRepository, jwtManager *utils.JWTManager) *AuthService {
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	jwtManager *utils.JWTManager,
+	passwordPolicy *utils.PasswordPolicy,
+	bcryptCost int,
+) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
+		userRepo:       userRepo,
+		jwtManager:     jwtManager,
+		passwordPolicy: passwordPolicy,
+		bcryptCost:     bcryptCost,
 	}
 }
 
@@ -37,13 +51,16 @@ type LoginResult struct {
 
 // Login authenticates a user and returns tokens
 func (s *AuthService) Login(ctx context.Context, req *model.UserLogin) (*LoginResult, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// Find user by username
 	user, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrInvalidCredentials
 		}
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	// Verify password
@@ -51,10 +68,21 @@ func (s *AuthService) Login(ctx context.Context, req *model.UserLogin) (*LoginRe
 		return nil, ErrInvalidCredentials
 	}
 
+	// Upgrade the stored hash if it was created with a lower cost.
+	// A failure here must not block an otherwise valid login.
+	if utils.NeedsRehash(user.PasswordHash, s.bcryptCost) {
+		if err := s.rehashPassword(ctx, user.ID, req.Password); err != nil {
+			slog.WarnContext(ctx, "failed to upgrade password hash",
+				slog.Int64("user_id", user.ID),
+				slog.Any("error", err),
+			)
+		}
+	}
+
 	// Generate tokens
 	tokens, err := s.jwtManager.GenerateTokenPair(user.ID, user.Username)
 	if err != nil {
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	return &LoginResult{
@@ -65,46 +93,59 @@ func (s *AuthService) Login(ctx context.Context, req *model.UserLogin) (*LoginRe
 
 // RefreshTokens generates new tokens using a refresh token
 func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*utils.TokenPair, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RefreshTokens")
+	defer span.End()
+
 	// Validate refresh token
 	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidRefreshToken.WithCause(err)
 	}
 
 	// Verify user still exists
 	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, ErrUserNotFound
+			return nil, ErrInvalidRefreshToken.WithCause(ErrUserNotFound)
 		}
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	// Generate new tokens
-	return s.jwtManager.GenerateTokenPair(user.ID, user.Username)
+	tokens, err := s.jwtManager.GenerateTokenPair(user.ID, user.Username)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return tokens, nil
 }
 
 // GetCurrentUser retrieves the current user by ID
 func (s *AuthService) GetCurrentUser(ctx context.Context, userID int64) (*model.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetCurrentUser")
+	defer span.End()
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrUserNotFound
 		}
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 	return user.ToResponse(), nil
 }
 
 // ChangePassword changes the user's password
 func (s *AuthService) ChangePassword(ctx context.Context, userID int64, req *model.PasswordChange) error {
+	ctx, span := tracing.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
 	// Get current user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrUserNotFound
 		}
-		return err
+		return apperr.Internal(err)
 	}
 
 	// Verify old password
@@ -112,36 +153,67 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int64, req *mod
 		return ErrInvalidOldPassword
 	}
 
+	// Enforce password policy
+	if s.passwordPolicy != nil {
+		if err := s.passwordPolicy.Validate(req.NewPassword, user.Username, user.Email); err != nil {
+			var policyErr *utils.PasswordPolicyError
+			if errors.As(err, &policyErr) {
+				return ErrPasswordPolicyFailed.WithCause(err).WithFields(apperr.FieldError{
+					Field:   "new_password",
+					Rule:    policyErr.Rule,
+					Param:   policyErr.Param,
+					Message: policyErr.Reason,
+				})
+			}
+			return apperr.Internal(err)
+		}
+	}
+
 	// Hash new password
-	newHash, err := utils.HashPassword(req.NewPassword)
+	newHash, err := utils.HashPasswordWithCost(req.NewPassword, s.bcryptCost)
 	if err != nil {
-		return err
+		return apperr.Internal(err)
 	}
 
 	// Update password
+	if err := s.userRepo.UpdatePassword(ctx, userID, newHash); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// rehashPassword stores a fresh hash of the password using the configured cost
+func (s *AuthService) rehashPassword(ctx context.Context, userID int64, password string) error {
+	newHash, err := utils.HashPasswordWithCost(password, s.bcryptCost)
+	if err != nil {
+		return err
+	}
 	return s.userRepo.UpdatePassword(ctx, userID, newHash)
 }
 
 // UpdateProfile updates the user's profile
 func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, req *model.UserUpdate) (*model.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateProfile")
+	defer span.End()
+
 	// Verify user exists
 	_, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrUserNotFound
 		}
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	// Update profile
 	if err := s.userRepo.UpdateProfile(ctx, userID, req); err != nil {
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	// Get updated user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, apperr.Internal(err)
 	}
 
 	return user.ToResponse(), nil
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+)
+
+// Date range and list limits for statistics
+const (
+	defaultStatsDays  = 30
+	maxStatsDays      = 366
+	overviewTopLimit  = 5
+	defaultPostsLimit = 20
+	maxPostsLimit     = 100
+)
+
+var (
+	ErrInvalidDateRange = apperr.New(apperr.KindValidation, "Invalid date range")
+	ErrPostNotFound     = apperr.New(apperr.KindNotFound, "Post not found")
+)
+
+// StatsService provides the view analytics for the admin dashboard
+type StatsService struct {
+	statsRepo *repository.StatsRepository
+}
+
+// NewStatsService creates a new statistics service
+func NewStatsService(statsRepo *repository.StatsRepository) *StatsService {
+	return &StatsService{statsRepo: statsRepo}
+}
+
+// dateRange is an inclusive range of UTC days
+type dateRange struct {
+	from, to time.Time
+}
+
+// parseDateRange reads from and to as YYYY-MM-DD. Either may be empty:
+// to defaults to today and from to the 30 days ending at to.
+func parseDateRange(from, to string) (dateRange, error) {
+	var r dateRange
+	var err error
+
+	r.to = time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		if r.to, err = time.Parse(time.DateOnly, to); err != nil {
+			return r, invalidDate("to", "must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	r.from = r.to.AddDate(0, 0, -(defaultStatsDays - 1))
+	if from != "" {
+		if r.from, err = time.Parse(time.DateOnly, from); err != nil {
+			return r, invalidDate("from", "must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	if r.from.After(r.to) {
+		return r, invalidDate("from", "must not be after to")
+	}
+	if r.days() > maxStatsDays {
+		return r, invalidDate("from", fmt.Sprintf("range must not exceed %d days", maxStatsDays))
+	}
+	return r, nil
+}
+
+// invalidDate reports a problem with one of the range parameters
+func invalidDate(field, message string) error {
+	return ErrInvalidDateRange.WithFields(apperr.FieldError{
+		Field:   field,
+		Rule:    "date_range",
+		Message: message,
+	})
+}
+
+// days returns the number of days in the range
+func (r dateRange) days() int {
+	return int(r.to.Sub(r.from).Hours()/24) + 1
+}
+
+// bounds returns the range as query parameters
+func (r dateRange) bounds() (string, string) {
+	return r.from.Format(time.DateOnly), r.to.Format(time.DateOnly)
+}
+
+// fillSeries returns one entry per day of the range, with zero views for
+// days missing from rows
+func (r dateRange) fillSeries(rows []model.DailyViews) ([]model.DailyViews, int64) {
+	views := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		views[row.Day.UTC().Format(time.DateOnly)] += row.Views
+	}
+
+	series := make([]model.DailyViews, 0, r.days())
+	var total int64
+	for day := r.from; !day.After(r.to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		series = append(series, model.DailyViews{Day: day, Date: date, Views: views[date]})
+		total += views[date]
+	}
+	return series, total
+}
+
+// Overview returns the dashboard summary for the date range
+func (s *StatsService) Overview(ctx context.Context, from, to string) (*model.StatsOverview, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.Overview")
+	defer span.End()
+
+	r, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	start, end := r.bounds()
+
+	daily, err := s.statsRepo.DailyViews(ctx, start, end, 0)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	series, total := r.fillSeries(daily)
+
+	overview := &model.StatsOverview{From: start, To: end, TotalViews: total, Series: series}
+
+	if overview.Devices, err = s.statsRepo.DeviceViews(ctx, start, end, 0); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if overview.TopReferrers, err = s.statsRepo.TopReferrers(ctx, start, end, 0, overviewTopLimit); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if overview.TopPosts, err = s.statsRepo.TopPosts(ctx, start, end, overviewTopLimit); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if overview.Posts, err = s.statsRepo.CountPostsByStatus(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if overview.Comments, err = s.statsRepo.CountCommentsByStatus(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return overview, nil
+}
+
+// Posts ranks posts by views within the date range
+func (s *StatsService) Posts(ctx context.Context, from, to string, limit int) (*model.PostStatsList, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.Posts")
+	defer span.End()
+
+	r, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	start, end := r.bounds()
+
+	if limit <= 0 {
+		limit = defaultPostsLimit
+	}
+	limit = min(limit, maxPostsLimit)
+
+	posts, err := s.statsRepo.TopPosts(ctx, start, end, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &model.PostStatsList{From: start, To: end, Posts: posts}, nil
+}
+
+// Post returns the analytics of a single post within the date range
+func (s *StatsService) Post(ctx context.Context, id int64, from, to string) (*model.PostStats, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.Post")
+	defer span.End()
+
+	r, err := parseDateRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	start, end := r.bounds()
+
+	post, err := s.statsRepo.PostViews(ctx, start, end, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, apperr.Internal(err)
+	}
+
+	daily, err := s.statsRepo.DailyViews(ctx, start, end, id)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	series, _ := r.fillSeries(daily)
+
+	stats := &model.PostStats{From: start, To: end, Post: *post, Series: series}
+	if stats.Devices, err = s.statsRepo.DeviceViews(ctx, start, end, id); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if stats.Referrers, err = s.statsRepo.TopReferrers(ctx, start, end, id, maxPostsLimit); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return stats, nil
+}
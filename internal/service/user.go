@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/model"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+	"github.com/aliaxy/byte-cabinet/internal/tracing"
+	"github.com/aliaxy/byte-cabinet/pkg/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrUsernameTaken = apperr.New(apperr.KindConflict, "Username is already taken")
+	ErrEmailTaken    = apperr.New(apperr.KindConflict, "Email is already in use")
+)
+
+// UserService handles user administration
+type UserService struct {
+	db             *database.DB
+	userRepo       *repository.UserRepository
+	passwordPolicy *utils.PasswordPolicy
+	bcryptCost     int
+}
+
+// NewUserService creates a new user service
+func NewUserService(
+	db *database.DB,
+	userRepo *repository.UserRepository,
+	passwordPolicy *utils.PasswordPolicy,
+	bcryptCost int,
+) *UserService {
+	return &UserService{
+		db:             db,
+		userRepo:       userRepo,
+		passwordPolicy: passwordPolicy,
+		bcryptCost:     bcryptCost,
+	}
+}
+
+// Create creates a user after checking uniqueness and the password policy
+func (s *UserService) Create(ctx context.Context, req *model.UserCreate) (*model.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	// Hash before the transaction so the write lock isn't held during bcrypt
+	if err := s.checkPassword(req.Password, "password", req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
+	hash, err := utils.HashPasswordWithCost(req.Password, s.bcryptCost)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	displayName := req.DisplayName
+	if displayName == "" {
+		displayName = req.Username
+	}
+
+	user := &model.User{
+		Username:     req.Username,
+		Email:        req.Email,
+		PasswordHash: hash,
+		DisplayName:  displayName,
+	}
+
+	// The checks give a specific error for the common case. They don't
+	// stop a concurrent create on PostgreSQL, where both transactions can
+	// pass them; the unique constraints catch that one.
+	err = s.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		repo := s.userRepo.WithTx(tx)
+
+		exists, err := repo.ExistsByUsername(ctx, req.Username)
+		if err != nil {
+			return apperr.Internal(err)
+		}
+		if exists {
+			return ErrUsernameTaken
+		}
+
+		exists, err = repo.ExistsByEmail(ctx, req.Email)
+		if err != nil {
+			return apperr.Internal(err)
+		}
+		if exists {
+			return ErrEmailTaken
+		}
+
+		if err := repo.Create(ctx, user); err != nil {
+			if errors.Is(err, repository.ErrUserAlreadyExists) {
+				return err
+			}
+			return apperr.Internal(err)
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrUserAlreadyExists) {
+		return nil, s.takenError(ctx, req.Username)
+	}
+	if err != nil {
+		// Begin and commit failures are the only errors not yet classified
+		if _, ok := apperr.As(err); ok {
+			return nil, err
+		}
+		return nil, apperr.Internal(err)
+	}
+
+	return user.ToResponse(), nil
+}
+
+// takenError tells which of the username and email was taken by a
+// concurrent create
+func (s *UserService) takenError(ctx context.Context, username string) error {
+	exists, err := s.userRepo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if exists {
+		return ErrUsernameTaken
+	}
+	return ErrEmailTaken
+}
+
+// ResetPassword sets a new password without requiring the current one
+func (s *UserService) ResetPassword(ctx context.Context, username, password string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return apperr.Internal(err)
+	}
+
+	if err := s.checkPassword(password, "password", user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hash, err := utils.HashPasswordWithCost(password, s.bcryptCost)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// List returns all users
+func (s *UserService) List(ctx context.Context) ([]*model.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.List")
+	defer span.End()
+
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	result := make([]*model.UserResponse, 0, len(users))
+	for i := range users {
+		result = append(result, users[i].ToResponse())
+	}
+	return result, nil
+}
+
+// checkPassword applies the password policy, reporting a violation as a
+// validation error on the given field
+func (s *UserService) checkPassword(password, field string, personal ...string) error {
+	if s.passwordPolicy == nil {
+		return nil
+	}
+
+	err := s.passwordPolicy.Validate(password, personal...)
+	if err == nil {
+		return nil
+	}
+
+	var policyErr *utils.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return ErrPasswordPolicyFailed.WithCause(err).WithFields(apperr.FieldError{
+			Field:   field,
+			Rule:    policyErr.Rule,
+			Param:   policyErr.Param,
+			Message: policyErr.Reason,
+		})
+	}
+	return apperr.Internal(err)
+}
@@ -0,0 +1,66 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewConfig builds the server TLS configuration using the reloader for
+// certificates. fasthttp only speaks HTTP/1.1, so no other protocol is
+// advertised via ALPN.
+func NewConfig(reloader *CertReloader, minVersion string) (*tls.Config, error) {
+	version, err := ParseVersion(minVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     version,
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"http/1.1"},
+	}, nil
+}
+
+// ParseVersion converts "1.2" or "1.3" to a tls version constant
+func ParseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", version)
+	}
+}
+
+// NewRedirectServer creates an HTTP server on addr that permanently
+// redirects every request to the same URL over HTTPS on httpsPort
+func NewRedirectServer(addr string, httpsPort int) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       30 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			host = strings.Trim(host, "[]")
+			switch {
+			case httpsPort != 443:
+				host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+			case strings.Contains(host, ":"):
+				// IPv6 literals need their brackets back
+				host = "[" + host + "]"
+			}
+
+			target := "https://" + host + r.URL.RequestURI()
+			http.Redirect(w, r, target, http.StatusPermanentRedirect)
+		}),
+	}
+}
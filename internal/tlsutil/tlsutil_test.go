@@ -0,0 +1,59 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		httpsPort int
+		want      string
+	}{
+		{"custom port", "http://blog.example.com:8080/posts/1?page=2", 8443, "https://blog.example.com:8443/posts/1?page=2"},
+		{"default port", "http://blog.example.com/about", 443, "https://blog.example.com/about"},
+		{"no port in host", "http://blog.example.com/", 8443, "https://blog.example.com:8443/"},
+		{"ipv6 host", "http://[::1]:8080/", 8443, "https://[::1]:8443/"},
+		{"ipv6 default port", "http://[::1]:8080/", 443, "https://[::1]/"},
+		{"ipv6 without port", "http://[::1]/", 443, "https://[::1]/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewRedirectServer(":0", tt.httpsPort)
+			rec := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Errorf("status %d, want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    uint16
+		wantErr bool
+	}{
+		{"", tls.VersionTLS12, false},
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"1.1", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.version)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v, error %v", tt.version, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
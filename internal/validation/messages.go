@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+	"github.com/aliaxy/byte-cabinet/pkg/utils"
+
+	ut "github.com/go-playground/universal-translator"
+)
+
+// messages holds translations for error messages that don't come from
+// validator tags: password policy rules, keyed by rule, and the top-level
+// messages of validation errors, keyed by their English text. The English
+// entries match the text the errors are created with.
+var messages = map[string]map[string]string{
+	LocaleEnglish: {
+		utils.PasswordRuleMinLength: "Password must be at least {0} characters long",
+		utils.PasswordRuleMaxLength: "Password must be at most {0} bytes long",
+		utils.PasswordRuleUppercase: "Password must contain at least one uppercase letter",
+		utils.PasswordRuleLowercase: "Password must contain at least one lowercase letter",
+		utils.PasswordRuleDigit:     "Password must contain at least one digit",
+		utils.PasswordRuleSymbol:    "Password must contain at least one symbol",
+		utils.PasswordRuleCommon:    "Password is too common, please choose a less predictable one",
+		utils.PasswordRulePersonal:  "Password must not contain your username or email",
+	},
+	LocaleChinese: {
+		utils.PasswordRuleMinLength: "密码长度必须至少为{0}个字符",
+		utils.PasswordRuleMaxLength: "密码长度不能超过{0}个字节",
+		utils.PasswordRuleUppercase: "密码必须至少包含一个大写字母",
+		utils.PasswordRuleLowercase: "密码必须至少包含一个小写字母",
+		utils.PasswordRuleDigit:     "密码必须至少包含一个数字",
+		utils.PasswordRuleSymbol:    "密码必须至少包含一个符号",
+		utils.PasswordRuleCommon:    "密码过于常见，请选择更难猜测的密码",
+		utils.PasswordRulePersonal:  "密码不能包含用户名或邮箱",
+
+		"Invalid request data":                       "请求数据无效",
+		"Invalid referrer":                           "来源无效",
+		"Invalid date range":                         "日期范围无效",
+		"Invalid profile data":                       "个人资料无效",
+		"Username and password are required":         "用户名和密码为必填项",
+		"Both old and new passwords are required":    "旧密码和新密码均为必填项",
+		"Refresh token is required":                  "刷新令牌为必填项",
+		"Password does not meet the password policy": "密码不符合密码策略",
+	},
+}
+
+// Message translates a password policy rule or a validation error message
+// into the given locale, falling back to English for unknown locales.
+// It reports false when there is no translation for key.
+func Message(key, locale string, params ...string) (string, bool) {
+	trans, _ := uni.GetTranslator(locale)
+	msg, err := trans.T(key, params...)
+	if err != nil {
+		return "", false
+	}
+	return msg, true
+}
+
+// LocalizeFields returns fields with the message of each password policy
+// rule translated into the given locale. Other fields are kept as is.
+func LocalizeFields(fields []apperr.FieldError, locale string) []apperr.FieldError {
+	localized := make([]apperr.FieldError, len(fields))
+	for i, fe := range fields {
+		if _, ok := messages[LocaleEnglish][fe.Rule]; ok {
+			if msg, ok := Message(fe.Rule, locale, fe.Param); ok {
+				fe.Message = msg
+			}
+		}
+		localized[i] = fe
+	}
+	return localized
+}
+
+// registerMessages adds the entries of messages to the translator of
+// their locale
+func registerMessages(u *ut.UniversalTranslator) {
+	for locale, entries := range messages {
+		trans, _ := u.GetTranslator(locale)
+		for key, text := range entries {
+			if err := trans.Add(key, text, false); err != nil {
+				panic("validation: register " + locale + " message " + key + ": " + err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/internal/apperr"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	zhtranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// Supported message locales; the first one is the fallback
+const (
+	LocaleEnglish = "en"
+	LocaleChinese = "zh"
+)
+
+// Locales lists the supported locales in order of preference
+var Locales = []string{LocaleEnglish, LocaleChinese}
+
+var (
+	validate = newValidator()
+	uni      = newUniversalTranslator()
+)
+
+// Validator returns the shared validator. Its translations are registered
+// once, so every handler must validate with this instance for Fields to
+// produce localized messages.
+func Validator() *validator.Validate {
+	return validate
+}
+
+// Fields converts validation errors into field errors with messages in the
+// given locale, falling back to English for unknown locales
+func Fields(errs validator.ValidationErrors, locale string) []apperr.FieldError {
+	trans, _ := uni.GetTranslator(locale)
+
+	fields := make([]apperr.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, apperr.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return fields
+}
+
+// newValidator creates a validator that reports fields by their JSON name
+// and has default messages registered for every supported locale
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(jsonFieldName)
+	return v
+}
+
+// jsonFieldName reports struct fields by their JSON name, so field errors
+// match the request body the client sent
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// newUniversalTranslator registers the default validator messages and the
+// message catalog for every supported locale on the shared validator
+func newUniversalTranslator() *ut.UniversalTranslator {
+	enLocale := en.New()
+	u := ut.New(enLocale, enLocale, zh.New())
+
+	enTrans, _ := u.GetTranslator(LocaleEnglish)
+	if err := entranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		panic("validation: register en translations: " + err.Error())
+	}
+
+	zhTrans, _ := u.GetTranslator(LocaleChinese)
+	if err := zhtranslations.RegisterDefaultTranslations(validate, zhTrans); err != nil {
+		panic("validation: register zh translations: " + err.Error())
+	}
+
+	registerMessages(u)
+	return u
+}
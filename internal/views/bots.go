@@ -0,0 +1,28 @@
+package views
+
+import "strings"
+
+// botMarkers are lowercase user agent fragments of crawlers, link
+// previewers, monitoring services and HTTP libraries
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "mediapartners", "archiver",
+	"facebookexternalhit", "embedly", "preview", "whatsapp", "skype",
+	"headless", "lighthouse", "pingdom", "uptime", "monitor", "prerender",
+	"curl", "wget", "python-", "go-http-client", "java/", "okhttp",
+	"libwww", "httpclient", "axios", "node-fetch", "feed", "rss",
+}
+
+// IsBot reports whether the user agent belongs to an automated client.
+// An empty user agent is treated as a bot since browsers always send one.
+func IsBot(userAgent string) bool {
+	if userAgent == "" {
+		return true
+	}
+	ua := strings.ToLower(userAgent)
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
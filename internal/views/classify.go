@@ -0,0 +1,52 @@
+package views
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/internal/model"
+)
+
+// maxDomainLength is the longest valid DNS name
+const maxDomainLength = 253
+
+// DeviceClass derives a coarse device class from the user agent
+func DeviceClass(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return model.DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "android"):
+		return model.DeviceMobile
+	default:
+		return model.DeviceDesktop
+	}
+}
+
+// ReferrerDomain reduces a referrer URL to its domain without a leading
+// "www.". It returns "" for direct visits, links from siteHost itself and
+// anything that is not an http(s) URL, so paths and query strings (which
+// may identify the visitor) are never kept.
+func ReferrerDomain(referrer, siteHost string) string {
+	u, err := url.Parse(referrer)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	host := normalizeHost(u.Hostname())
+	if host == "" || len(host) > maxDomainLength || host == normalizeHost(siteHost) {
+		return ""
+	}
+	return host
+}
+
+// normalizeHost lowercases host and strips a port and leading "www."
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return strings.TrimPrefix(host, "www.")
+}
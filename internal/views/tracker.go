@@ -0,0 +1,247 @@
+// Package views counts post views. Views are deduplicated per visitor and
+// day without storing personal data, crawlers are ignored, and counts are
+// buffered in memory and written to the database in batches, together with
+// daily aggregates by referrer domain and device class.
+package views
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aliaxy/byte-cabinet/internal/config"
+	"github.com/aliaxy/byte-cabinet/internal/database"
+	"github.com/aliaxy/byte-cabinet/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrPostNotFound is returned when a view is recorded for a post that does
+// not exist or is not published
+var ErrPostNotFound = errors.New("post not found")
+
+// finalFlushTimeout bounds the flush performed on shutdown
+const finalFlushTimeout = 5 * time.Second
+
+// Visit describes a single page view
+type Visit struct {
+	PostID    int64
+	IP        string
+	UserAgent string
+	Referrer  string // URL of the page that linked to the post
+}
+
+// dailyKey identifies a row of the daily analytics
+type dailyKey struct {
+	day      string
+	postID   int64
+	referrer string
+	device   string
+}
+
+// Tracker deduplicates and buffers post views
+type Tracker struct {
+	db       *database.DB
+	posts    *repository.PostRepository
+	cfg      config.ViewsConfig
+	siteHost string // referrers from this host count as internal
+
+	mu      sync.Mutex
+	day     string                // UTC date the salt and seen set belong to
+	salt    [32]byte              // random per day, never persisted
+	seen    map[[32]byte]struct{} // hashed visitor/post pairs counted today
+	full    bool                  // seen reached cfg.MaxVisitors today
+	pending map[int64]int64       // views per post not yet written
+	daily   map[dailyKey]int64    // daily analytics not yet written
+}
+
+// NewTracker creates a view tracker. siteURL is the blog's public URL,
+// used to tell internal navigation from external referrers.
+func NewTracker(db *database.DB, cfg config.ViewsConfig, siteURL string) *Tracker {
+	var siteHost string
+	if u, err := url.Parse(siteURL); err == nil {
+		siteHost = u.Hostname()
+	}
+
+	return &Tracker{
+		db:       db,
+		posts:    repository.NewPostRepository(db),
+		cfg:      cfg,
+		siteHost: siteHost,
+		pending:  make(map[int64]int64),
+		daily:    make(map[dailyKey]int64),
+	}
+}
+
+// Track records a view. It reports whether the view was counted: views
+// from bots and repeat views by the same visitor on the same day are not.
+// Once cfg.MaxVisitors visitors have been remembered in a day, further
+// views are counted without deduplication until the next UTC day, so
+// flooding the tracker can inflate counts but never stop counting.
+func (t *Tracker) Track(ctx context.Context, v Visit) (bool, error) {
+	if IsBot(v.UserAgent) {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	t.mu.Lock()
+	t.rotate(now)
+	key := t.visitorKey(v)
+	_, seen := t.seen[key]
+	t.mu.Unlock()
+	if seen {
+		return false, nil
+	}
+
+	// Only views that would count reach the database
+	published, err := t.posts.IsPublished(ctx, v.PostID)
+	if err != nil {
+		return false, err
+	}
+	if !published {
+		return false, ErrPostNotFound
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// The day may have rolled over or a concurrent request may have counted
+	// the same visit while the lock was released
+	t.rotate(now)
+	if _, seen := t.seen[key]; seen {
+		return false, nil
+	}
+	if len(t.seen) < t.cfg.MaxVisitors {
+		t.seen[key] = struct{}{}
+	} else if !t.full {
+		t.full = true
+		slog.WarnContext(ctx, "views.max_visitors reached, counting views without deduplication until the next UTC day",
+			slog.Int("max_visitors", t.cfg.MaxVisitors))
+	}
+	t.pending[v.PostID]++
+	t.daily[dailyKey{
+		day:      t.day,
+		postID:   v.PostID,
+		referrer: ReferrerDomain(v.Referrer, t.siteHost),
+		device:   DeviceClass(v.UserAgent),
+	}]++
+	return true, nil
+}
+
+// rotate starts a new day with a fresh salt, forgetting all visitors.
+// The caller must hold t.mu.
+func (t *Tracker) rotate(now time.Time) {
+	day := now.Format(time.DateOnly)
+	if day == t.day {
+		return
+	}
+	t.day = day
+	t.seen = make(map[[32]byte]struct{})
+	t.full = false
+	if _, err := rand.Read(t.salt[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+}
+
+// visitorKey hashes the visitor and post with the daily salt, so visitors
+// cannot be recognised across days or recovered from the hash.
+// The caller must hold t.mu.
+func (t *Tracker) visitorKey(v Visit) [32]byte {
+	h := sha256.New()
+	h.Write(t.salt[:])
+	h.Write([]byte(v.IP))
+	h.Write([]byte{0})
+	h.Write([]byte(v.UserAgent))
+	h.Write([]byte{0})
+	binary.Write(h, binary.BigEndian, v.PostID)
+
+	var key [32]byte
+	h.Sum(key[:0])
+	return key
+}
+
+// Flush writes the buffered counts in a single transaction. Counts for
+// posts deleted since the view are dropped. If the write fails because the
+// database is busy the counts are kept for the next flush; on any other
+// error they are dropped so one bad entry cannot block later flushes.
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	pending, daily := t.pending, t.daily
+	t.pending = make(map[int64]int64)
+	t.daily = make(map[dailyKey]int64)
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := t.db.WithTx(ctx, func(tx *sqlx.Tx) error {
+		posts := t.posts.WithTx(tx)
+		deleted := make(map[int64]bool)
+		for id, views := range pending {
+			exists, err := posts.AddViews(ctx, id, views)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				deleted[id] = true
+			}
+		}
+		for k, views := range daily {
+			if deleted[k.postID] {
+				continue
+			}
+			if err := posts.AddDailyViews(ctx, k.day, k.postID, k.referrer, k.device, views); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if !t.db.IsRetryable(err) {
+			return fmt.Errorf("dropped views of %d post(s): %w", len(pending), err)
+		}
+		t.mu.Lock()
+		for id, views := range pending {
+			t.pending[id] += views
+		}
+		for k, views := range daily {
+			t.daily[k] += views
+		}
+		t.mu.Unlock()
+		return err
+	}
+
+	slog.DebugContext(ctx, "post views flushed", slog.Int("posts", len(pending)))
+	return nil
+}
+
+// Run flushes on cfg.FlushInterval until ctx is cancelled, then flushes
+// once more so no counted views are lost on shutdown
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+			defer cancel()
+			if err := t.Flush(flushCtx); err != nil {
+				slog.Error("failed to flush post views on shutdown", slog.Any("error", err))
+			}
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				slog.Error("failed to flush post views", slog.Any("error", err))
+			}
+		}
+	}
+}
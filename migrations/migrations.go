@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL schema migrations so the binary can
+// migrate the database without the migrations directory on disk.
+package migrations
+
+import "embed"
+
+// FS holds one directory of migrations per database dialect ("sqlite",
+// "postgres"), each named <version>_<title>.<up|down>.sql. Both sets must
+// keep the same versions so a schema version means the same on either.
+//
+//go:embed sqlite/*.sql postgres/*.sql
+var FS embed.FS
@@ -0,0 +1,9 @@
+-- Drop tables in reverse order of creation (respecting foreign key constraints)
+
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
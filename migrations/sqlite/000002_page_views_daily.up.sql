@@ -0,0 +1,15 @@
+-- Migration: 000002_page_views_daily
+-- Description: Daily post view aggregates for built-in analytics.
+-- Only counts are stored: no cookies, IP addresses or user agents.
+
+CREATE TABLE IF NOT EXISTS page_views_daily (
+    day DATE NOT NULL, -- UTC
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    referrer TEXT NOT NULL DEFAULT '', -- referring domain, empty for direct or internal visits
+    device TEXT NOT NULL CHECK(device IN ('desktop', 'mobile', 'tablet')),
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, post_id, referrer, device)
+);
+
+-- Per-post time series
+CREATE INDEX IF NOT EXISTS idx_page_views_daily_post_id ON page_views_daily(post_id, day);
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/aliaxy/byte-cabinet/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestIDKey is the attribute key under which request IDs are logged
+const RequestIDKey = "request_id"
+
+// localsKey is the fiber.Ctx locals key holding the request-scoped logger
+const localsKey = "logger"
+
+// level is shared by every logger created by New so it can be changed at runtime
+var level = new(slog.LevelVar)
+
+// New creates a slog logger writing to w.
+// format is "text" or "json"; level is debug, info, warn or error.
+func New(w io.Writer, format, levelName string) (*slog.Logger, error) {
+	if err := SetLevel(levelName); err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// SetLevel changes the minimum level of all loggers created by New
+func SetLevel(levelName string) error {
+	l, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// ParseLevel converts a level name to a slog.Level
+func ParseLevel(levelName string) (slog.Level, error) {
+	switch strings.ToLower(levelName) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", levelName)
+	}
+}
+
+// WithFiber stores a request-scoped logger in the fiber context
+func WithFiber(c *fiber.Ctx, l *slog.Logger) {
+	c.Locals(localsKey, l)
+}
+
+// FromFiber returns the request-scoped logger, or the default logger when
+// none has been attached to the request
+func FromFiber(c *fiber.Ctx) *slog.Logger {
+	if l, ok := c.Locals(localsKey).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// contextHandler adds the request ID carried by the context to records
+// logged with a *Context method, so code without access to the fiber
+// context still produces correlated log lines
+type contextHandler struct {
+	slog.Handler
+	hasRequestID bool // the ID was already attached with Logger.With
+}
+
+// Handle adds the request ID before passing the record on
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.hasRequestID {
+		if id := requestid.FromContext(ctx); id != "" {
+			r.AddAttrs(slog.String(RequestIDKey, id))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs remembers whether the request ID is among the attributes
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hasRequestID := h.hasRequestID
+	for _, a := range attrs {
+		if a.Key == RequestIDKey {
+			hasRequestID = true
+		}
+	}
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs), hasRequestID: hasRequestID}
+}
+
+// WithGroup returns a handler for the group
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name), hasRequestID: h.hasRequestID}
+}
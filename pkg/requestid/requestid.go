@@ -0,0 +1,64 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Header is the HTTP header carrying the request ID
+const Header = fiber.HeaderXRequestID
+
+// localsKey is the fiber.Ctx locals key holding the request ID
+const localsKey = "requestid"
+
+// maxLength bounds the length of request IDs accepted from clients
+const maxLength = 128
+
+// contextKey is the context.Context key holding the request ID
+type contextKey struct{}
+
+// New generates a new random request ID
+func New() string {
+	return utils.UUIDv4()
+}
+
+// Valid reports whether an ID supplied by a client is safe to reuse.
+// Only printable ASCII without spaces or quotes is accepted, so the ID can
+// be written to logs and headers verbatim.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		if ch <= ' ' || ch > '~' || ch == '"' || ch == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// WithFiber stores the request ID in the fiber context locals
+func WithFiber(c *fiber.Ctx, id string) {
+	c.Locals(localsKey, id)
+}
+
+// FromFiber returns the request ID of the current request, or an empty
+// string when the request ID middleware has not run
+func FromFiber(c *fiber.Ctx) string {
+	id, _ := c.Locals(localsKey).(string)
+	return id
+}
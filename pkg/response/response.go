@@ -1,6 +1,11 @@
 package response
 
 import (
+	"log/slog"
+
+	"github.com/aliaxy/byte-cabinet/pkg/logger"
+	"github.com/aliaxy/byte-cabinet/pkg/requestid"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,7 +19,17 @@ type Response struct {
 
 // ErrorInfo represents error details in a response
 type ErrorInfo struct {
-	Code    string `json:"code"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError represents a validation failure for a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Param   string `json:"param,omitempty"`
 	Message string `json:"message"`
 }
 
@@ -43,6 +58,8 @@ const (
 	ErrCodeRateLimited  = "RATE_LIMITED"
 	ErrCodeBadRequest   = "BAD_REQUEST"
 	ErrCodeInvalidInput = "INVALID_INPUT"
+	ErrCodeUnavailable  = "SERVICE_UNAVAILABLE"
+	ErrCodeTooLarge     = "PAYLOAD_TOO_LARGE"
 )
 
 // OK sends a success response with data
@@ -99,12 +116,23 @@ func Paginated(c *fiber.Ctx, items interface{}, page, pageSize int, total int64)
 
 // Error sends an error response with the given status code
 func Error(c *fiber.Ctx, status int, code, message string) error {
+	return ErrorWithInfo(c, status, &ErrorInfo{
+		Code:    code,
+		Message: message,
+	}, nil)
+}
+
+// ErrorWithInfo sends an error response with full error details and
+// optional data. The request ID is filled in so clients can quote it when
+// reporting a problem.
+func ErrorWithInfo(c *fiber.Ctx, status int, info *ErrorInfo, data interface{}) error {
+	if info.RequestID == "" {
+		info.RequestID = requestid.FromFiber(c)
+	}
 	return c.Status(status).JSON(Response{
 		Success: false,
-		Error: &ErrorInfo{
-			Code:    code,
-			Message: message,
-		},
+		Data:    data,
+		Error:   info,
 	})
 }
 
@@ -165,7 +193,10 @@ func InternalError(c *fiber.Ctx, message string) error {
 
 // InternalErrorWithLog sends a 500 error and logs the actual error
 func InternalErrorWithLog(c *fiber.Ctx, err error) error {
-	// In production, you'd want to log this error
-	// log.Printf("Internal error: %v", err)
+	logger.FromFiber(c).Error("internal error",
+		slog.String("method", c.Method()),
+		slog.String("path", c.Path()),
+		slog.Any("error", err),
+	)
 	return InternalError(c, "An internal error occurred")
 }
@@ -23,6 +23,19 @@ func CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
+// NeedsRehash reports whether a bcrypt hash was generated with a cost lower
+// than the given cost and should be recomputed on the next successful login
+func NeedsRehash(hash string, cost int) bool {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultCost
+	}
+	hashCost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return hashCost < cost
+}
+
 // HashPasswordWithCost generates a bcrypt hash with a custom cost factor
 func HashPasswordWithCost(password string, cost int) (string, error) {
 	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
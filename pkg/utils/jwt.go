@@ -131,9 +131,10 @@ func (m *JWTManager) ValidateRefreshToken(tokenString string) (*Claims, error) {
 
 // TokenPair represents a pair of access and refresh tokens
 type TokenPair struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"` // Access token expiration in seconds
+	AccessToken      string `json:"access_token,omitempty"`
+	RefreshToken     string `json:"refresh_token,omitempty"` // Omitted when delivered as a cookie
+	ExpiresIn        int64  `json:"expires_in"`              // Access token expiration in seconds
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`      // Refresh token expiration in seconds
 }
 
 // GenerateTokenPair generates both access and refresh tokens
@@ -149,8 +150,9 @@ func (m *JWTManager) GenerateTokenPair(userID int64, username string) (*TokenPai
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(m.accessTokenTTL.Seconds()),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(m.accessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(m.refreshTokenTTL.Seconds()),
 	}, nil
 }
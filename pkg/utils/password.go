@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ErrWeakPassword is matched by every PasswordPolicyError via errors.Is
+var ErrWeakPassword = errors.New("password does not meet policy")
+
+//go:embed common_passwords.txt
+var commonPasswordsData string
+
+var (
+	commonPasswords     map[string]struct{}
+	commonPasswordsOnce sync.Once
+)
+
+// Rules a password can fail, reported in PasswordPolicyError.Rule so
+// callers can localize the reason
+const (
+	PasswordRuleMinLength = "password_min_length"
+	PasswordRuleMaxLength = "password_max_length"
+	PasswordRuleUppercase = "password_uppercase"
+	PasswordRuleLowercase = "password_lowercase"
+	PasswordRuleDigit     = "password_digit"
+	PasswordRuleSymbol    = "password_symbol"
+	PasswordRuleCommon    = "password_common"
+	PasswordRulePersonal  = "password_personal"
+)
+
+// PasswordPolicyError describes why a password was rejected
+type PasswordPolicyError struct {
+	Rule   string // one of the PasswordRule constants
+	Param  string // rule parameter, e.g. the minimum length
+	Reason string // English description
+}
+
+// Error returns the human readable reason
+func (e *PasswordPolicyError) Error() string {
+	return e.Reason
+}
+
+// Is reports whether target is ErrWeakPassword
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
+
+// PasswordPolicy defines the rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	RejectCommon     bool
+	RejectPersonal   bool
+}
+
+// Validate checks a password against the policy.
+// personal holds user attributes (username, email) the password must not contain.
+func (p *PasswordPolicy) Validate(password string, personal ...string) error {
+	length := len([]rune(password))
+	if p.MinLength > 0 && length < p.MinLength {
+		return policyError(PasswordRuleMinLength, strconv.Itoa(p.MinLength), "Password must be at least %d characters long", p.MinLength)
+	}
+	// bcrypt only considers the first 72 bytes
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return policyError(PasswordRuleMaxLength, strconv.Itoa(p.MaxLength), "Password must be at most %d bytes long", p.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUppercase && !hasUpper {
+		return policyError(PasswordRuleUppercase, "", "Password must contain at least one uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		return policyError(PasswordRuleLowercase, "", "Password must contain at least one lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		return policyError(PasswordRuleDigit, "", "Password must contain at least one digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		return policyError(PasswordRuleSymbol, "", "Password must contain at least one symbol")
+	}
+
+	lower := strings.ToLower(password)
+
+	if p.RejectCommon && IsCommonPassword(lower) {
+		return policyError(PasswordRuleCommon, "", "Password is too common, please choose a less predictable one")
+	}
+
+	if p.RejectPersonal {
+		for _, value := range personal {
+			if containsPersonalInfo(lower, value) {
+				return policyError(PasswordRulePersonal, "", "Password must not contain your username or email")
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsCommonPassword reports whether the password appears in the embedded list
+func IsCommonPassword(password string) bool {
+	commonPasswordsOnce.Do(loadCommonPasswords)
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
+
+// loadCommonPasswords parses the embedded common password list
+func loadCommonPasswords() {
+	commonPasswords = make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsData))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		commonPasswords[strings.ToLower(line)] = struct{}{}
+	}
+}
+
+// containsPersonalInfo reports whether the lowercased password contains the
+// given value, or the local part of it when the value is an email address
+func containsPersonalInfo(password, value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if local, _, ok := strings.Cut(value, "@"); ok {
+		if password == value {
+			return true
+		}
+		value = local
+	}
+	// Very short values would reject too many legitimate passwords
+	if len(value) < 3 {
+		return false
+	}
+	return strings.Contains(password, value)
+}
+
+// policyError creates a PasswordPolicyError for rule with a formatted reason
+func policyError(rule, param, format string, args ...interface{}) error {
+	return &PasswordPolicyError{Rule: rule, Param: param, Reason: fmt.Sprintf(format, args...)}
+}
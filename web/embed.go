@@ -0,0 +1,22 @@
+//go:build embedweb
+
+// Package web provides the built frontend when the binary is compiled with
+// the embedweb build tag (after "pnpm build" has produced web/dist).
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dist returns the built frontend, or nil when it was not embedded
+func Dist() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		return nil
+	}
+	return sub
+}
@@ -0,0 +1,12 @@
+//go:build !embedweb
+
+// Package web provides the built frontend when the binary is compiled with
+// the embedweb build tag (after "pnpm build" has produced web/dist).
+package web
+
+import "io/fs"
+
+// Dist returns the built frontend, or nil when it was not embedded
+func Dist() fs.FS {
+	return nil
+}